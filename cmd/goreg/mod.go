@@ -1,12 +1,18 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
 
+	"github.com/magicdrive/goreg/internal/aliasescmd"
+	"github.com/magicdrive/goreg/internal/cache"
+	"github.com/magicdrive/goreg/internal/cachecmd"
 	"github.com/magicdrive/goreg/internal/commandline"
+	"github.com/magicdrive/goreg/internal/configcmd"
 	"github.com/magicdrive/goreg/internal/core"
+	"github.com/magicdrive/goreg/internal/explaincmd"
 	"github.com/magicdrive/goreg/internal/initcmd"
 )
 
@@ -19,6 +25,30 @@ func Execute(version string) {
 		return
 	}
 
+	// Check for config subcommand
+	if len(args) > 0 && args[0] == "config" {
+		ConfigCommand(args[1:])
+		return
+	}
+
+	// Check for cache subcommand
+	if len(args) > 0 && args[0] == "cache" {
+		CacheCommand(args[1:])
+		return
+	}
+
+	// Check for aliases subcommand
+	if len(args) > 0 && args[0] == "aliases" {
+		AliasesCommand(args[1:])
+		return
+	}
+
+	// Check for explain subcommand
+	if len(args) > 0 && args[0] == "explain" {
+		ExplainCommand(args[1:])
+		return
+	}
+
 	_, opt, err := commandline.OptParse(args)
 	if err != nil {
 		log.Fatalf("Faital Error: %v\n", err)
@@ -39,6 +69,24 @@ func Execute(version string) {
 		os.Exit(1)
 	}
 
+	resolveModulePath(opt)
+
+	if cacheVersion, ok := cache.Version(version); ok && !opt.NoCacheFlag {
+		if cacheDir, err := cache.Dir(); err == nil {
+			opt.Version = cacheVersion
+			opt.CacheDir = cacheDir
+		}
+	}
+
+	if err := core.Apply(opt); err != nil {
+		if errors.Is(err, core.ErrCheckFailed) {
+			os.Exit(1)
+		}
+		log.Fatal(err)
+	}
+}
+
+func resolveModulePath(opt *commandline.Option) {
 	if opt.ModulePath == "" {
 		if _modulePath, err := core.GetModulePath(); err != nil {
 			fmt.Println("Error: local modulepath not found. specify your local modulepath with --local option")
@@ -48,8 +96,13 @@ func Execute(version string) {
 		}
 	}
 
-	if err := core.Apply(opt); err != nil {
-		log.Fatal(err)
+	if root, err := core.GetModuleRoot(); err == nil {
+		opt.ModuleRoot = root
+	}
+
+	// Overrides may turn module subgrouping on for some files only.
+	if requires, err := core.GetModuleRequires(); err == nil {
+		opt.Requires = requires
 	}
 }
 
@@ -59,3 +112,59 @@ func InitCommand() {
 		os.Exit(1)
 	}
 }
+
+func ExplainCommand(args []string) {
+	_, opt, err := commandline.OptParse(args)
+	if err != nil {
+		log.Fatalf("Faital Error: %v\n", err)
+	}
+
+	if opt.FileName == "" {
+		fmt.Println("Error: a file name is required")
+		os.Exit(1)
+	}
+
+	resolveModulePath(opt)
+
+	if err := explaincmd.Execute(opt, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func AliasesCommand(args []string) {
+	unify, rest := aliasescmd.SplitArgs(args)
+	_, opt, err := commandline.OptParse(rest)
+	if err != nil {
+		log.Fatalf("Faital Error: %v\n", err)
+	}
+
+	if opt.FileName == "" {
+		opt.FileName = "."
+	}
+
+	resolveModulePath(opt)
+
+	if err := aliasescmd.Execute(opt, unify, os.Stdout); err != nil {
+		if !errors.Is(err, aliasescmd.ErrNotUnified) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func ConfigCommand(args []string) {
+	if err := configcmd.Execute(args, os.Stdout); err != nil {
+		if !errors.Is(err, configcmd.ErrInvalidConfig) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func CacheCommand(args []string) {
+	if err := cachecmd.Execute(args, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
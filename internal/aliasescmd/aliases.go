@@ -0,0 +1,245 @@
+package aliasescmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/magicdrive/goreg/internal/commandline"
+	"github.com/magicdrive/goreg/internal/core"
+)
+
+// ErrNotUnified is returned by a --unify run without --write when some
+// files would be rewritten.
+var ErrNotUnified = errors.New("aliases are not unified")
+
+// SplitArgs removes the --unify flag, which only this subcommand knows, from
+// args and reports whether it was given.
+func SplitArgs(args []string) (bool, []string) {
+	unify := false
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == "--unify" || arg == "-unify" {
+			unify = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return unify, rest
+}
+
+// sourceFile is a file aliases are counted in and may be unified in, with
+// its unified content once rewritten.
+type sourceFile struct {
+	name   string
+	src    []byte
+	opt    *commandline.Option
+	result []byte
+}
+
+// aliasCount is how often a package is used by one name.
+type aliasCount struct {
+	alias string
+	name  string
+	count int
+}
+
+// Execute reports the aliases every import path is used with in the files
+// at opt.FileName. With unify, every file is rewritten to use the alias
+// configured in [aliases] or else the most common name; without
+// opt.WriteFlag the files that would change are only listed. Generated files
+// and files goreg is told to skip are neither counted nor rewritten.
+func Execute(opt *commandline.Option, unify bool, w io.Writer) error {
+	files, err := readSources(opt)
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[string][]*aliasCount)
+	for _, file := range files {
+		uses, err := core.AliasUses(file.src, file.opt)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file.name, err)
+		}
+		for _, use := range uses {
+			counts[use.Path] = addUse(counts[use.Path], use)
+		}
+	}
+
+	if err := report(w, counts); err != nil {
+		return err
+	}
+	if !unify {
+		return nil
+	}
+
+	names := chooseNames(counts, opt.Aliases)
+
+	// Every file is rewritten before any is written, so a failure leaves
+	// none of them changed.
+	var changed []sourceFile
+	for _, file := range files {
+		unifyOpt := *file.opt
+		unifyOpt.Aliases = names
+		result, err := core.RewriteAliases(file.src, &unifyOpt)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file.name, err)
+		}
+		if !bytes.Equal(result, file.src) {
+			changed = append(changed, sourceFile{name: file.name, src: file.src, result: result})
+		}
+	}
+
+	for _, file := range changed {
+		if opt.WriteFlag {
+			if err := core.WriteFileAtomic(file.name, file.src, file.result, opt.BackupSuffix); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "unified %s\n", file.name)
+		} else {
+			fmt.Fprintf(w, "would unify %s\n", file.name)
+		}
+	}
+
+	if len(changed) > 0 && !opt.WriteFlag {
+		return ErrNotUnified
+	}
+	return nil
+}
+
+// readSources reads the files at opt.FileName with the options that apply
+// to each, leaving out the ones core.ShouldSkip skips.
+func readSources(opt *commandline.Option) ([]sourceFile, error) {
+	names, err := goFiles(opt)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []sourceFile
+	for _, name := range names {
+		fileOpt, err := opt.ForFile(name)
+		if err != nil {
+			return nil, err
+		}
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		if core.ShouldSkip(src, fileOpt) {
+			continue
+		}
+		files = append(files, sourceFile{name: name, src: src, opt: fileOpt})
+	}
+	return files, nil
+}
+
+func goFiles(opt *commandline.Option) ([]string, error) {
+	info, err := os.Stat(opt.FileName)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{opt.FileName}, nil
+	}
+
+	var files []string
+	err = core.WalkGoFiles(opt, opt.FileName, func(path string) error {
+		files = append(files, path)
+		return nil
+	})
+	return files, err
+}
+
+func addUse(counts []*aliasCount, use core.AliasUse) []*aliasCount {
+	for _, c := range counts {
+		if c.alias == use.Alias && c.name == use.Name {
+			c.count++
+			return counts
+		}
+	}
+	return append(counts, &aliasCount{alias: use.Alias, name: use.Name, count: 1})
+}
+
+// report writes the import paths used with an alias at least once, each
+// with its aliases from the most to the least common.
+func report(w io.Writer, counts map[string][]*aliasCount) error {
+	paths := make([]string, 0, len(counts))
+	for path, aliases := range counts {
+		for _, c := range aliases {
+			if c.alias != "" {
+				paths = append(paths, path)
+				break
+			}
+		}
+	}
+	sort.Strings(paths)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PATH\tALIAS\tCOUNT")
+	for _, path := range paths {
+		aliases := counts[path]
+		sort.SliceStable(aliases, func(i, j int) bool {
+			if aliases[i].count != aliases[j].count {
+				return aliases[i].count > aliases[j].count
+			}
+			return aliases[i].alias < aliases[j].alias
+		})
+		for _, c := range aliases {
+			alias := c.alias
+			if alias == "" {
+				alias = "-"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%d\n", path, alias, c.count)
+		}
+	}
+	return tw.Flush()
+}
+
+// chooseNames returns the name every import path should be used by: the
+// configured alias, or else the most common name, preferring the package
+// name and then the lexically smallest on ties. Paths used by one name only
+// are left out unless configured.
+func chooseNames(counts map[string][]*aliasCount, configured map[string]string) map[string]string {
+	result := make(map[string]string, len(configured))
+	for path, alias := range configured {
+		result[path] = alias
+	}
+
+	for path, aliases := range counts {
+		if _, ok := result[path]; ok {
+			continue
+		}
+
+		byName := make(map[string]int)
+		implicit := ""
+		for _, c := range aliases {
+			if !token.IsIdentifier(c.name) {
+				continue
+			}
+			byName[c.name] += c.count
+			if c.alias == "" {
+				implicit = c.name
+			}
+		}
+		if len(byName) < 2 {
+			continue
+		}
+
+		best := ""
+		for name, n := range byName {
+			switch {
+			case best == "" || n > byName[best]:
+				best = name
+			case n == byName[best] && best != implicit && (name == implicit || name < best):
+				best = name
+			}
+		}
+		result[path] = best
+	}
+	return result
+}
@@ -0,0 +1,187 @@
+package aliasescmd_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/magicdrive/goreg/internal/aliasescmd"
+	"github.com/magicdrive/goreg/internal/commandline"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+var files = map[string]string{
+	"a.go": `package p
+
+import pb "example.com/api/v1"
+
+var _ pb.Request
+`,
+	"b.go": `package p
+
+import pb "example.com/api/v1"
+
+var _ pb.Response
+`,
+	"c.go": `package p
+
+import (
+	apiv1 "example.com/api/v1"
+	"fmt"
+)
+
+var _ = fmt.Sprint(apiv1.Request{})
+`,
+}
+
+func TestExecute_Report(t *testing.T) {
+	dir := writeFiles(t, files)
+
+	var out bytes.Buffer
+	if err := aliasescmd.Execute(&commandline.Option{FileName: dir, NoGitignoreFlag: true}, false, &out); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	expected := `PATH                ALIAS  COUNT
+example.com/api/v1  pb     2
+example.com/api/v1  apiv1  1
+`
+	if out.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestExecute_Unify(t *testing.T) {
+	cases := []struct {
+		name     string
+		aliases  map[string]string
+		expected string
+	}{
+		{
+			name: "Most common alias",
+			expected: `package p
+
+import (
+	pb "example.com/api/v1"
+	"fmt"
+)
+
+var _ = fmt.Sprint(pb.Request{})
+`,
+		},
+		{
+			name:    "Configured alias",
+			aliases: map[string]string{"example.com/api/v1": "api"},
+			expected: `package p
+
+import (
+	api "example.com/api/v1"
+	"fmt"
+)
+
+var _ = fmt.Sprint(api.Request{})
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writeFiles(t, files)
+			opt := &commandline.Option{FileName: dir, NoGitignoreFlag: true, Aliases: tc.aliases}
+
+			var out bytes.Buffer
+			if err := aliasescmd.Execute(opt, true, &out); !errors.Is(err, aliasescmd.ErrNotUnified) {
+				t.Fatalf("expected ErrNotUnified, got %v", err)
+			}
+			if got, _ := os.ReadFile(filepath.Join(dir, "c.go")); string(got) != files["c.go"] {
+				t.Errorf("check mode must not write files, got:\n%s", got)
+			}
+
+			opt.WriteFlag = true
+			out.Reset()
+			if err := aliasescmd.Execute(opt, true, &out); err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			got, err := os.ReadFile(filepath.Join(dir, "c.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, got)
+			}
+
+			opt.WriteFlag = false
+			out.Reset()
+			if err := aliasescmd.Execute(opt, true, &out); err != nil {
+				t.Errorf("expected unified files, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	unify, rest := aliasescmd.SplitArgs([]string{"--unify", "-w", "./..."})
+	if !unify || len(rest) != 2 || rest[0] != "-w" {
+		t.Errorf("unexpected result: %v %v", unify, rest)
+	}
+}
+
+func TestExecute_SkipsGeneratedAndIgnoredFiles(t *testing.T) {
+	skipped := map[string]string{
+		"c.pb.go": `// Code generated by protoc-gen-go. DO NOT EDIT.
+
+package p
+
+import api "example.com/api/v1"
+
+var _ api.Request
+`,
+		"d.go": `//goreg:ignore
+
+package p
+
+import api "example.com/api/v1"
+
+var _ api.Response
+`,
+	}
+	all := map[string]string{}
+	for name, content := range files {
+		all[name] = content
+	}
+	for name, content := range skipped {
+		all[name] = content
+	}
+	dir := writeFiles(t, all)
+	opt := &commandline.Option{FileName: dir, NoGitignoreFlag: true, WriteFlag: true}
+
+	var out bytes.Buffer
+	if err := aliasescmd.Execute(opt, true, &out); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	expected := `PATH                ALIAS  COUNT
+example.com/api/v1  pb     2
+example.com/api/v1  apiv1  1
+unified ` + filepath.Join(dir, "c.go") + "\n"
+	if out.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+	for name, content := range skipped {
+		if got, _ := os.ReadFile(filepath.Join(dir, name)); string(got) != content {
+			t.Errorf("%s must not be rewritten, got:\n%s", name, got)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+)
+
+// Dir returns the directory goreg keeps its cache in:
+// $XDG_CACHE_HOME/goreg when XDG_CACHE_HOME is an absolute path, the
+// platform's user cache directory otherwise.
+func Dir() (string, error) {
+	if xdgCacheHome := os.Getenv("XDG_CACHE_HOME"); filepath.IsAbs(xdgCacheHome) {
+		return filepath.Join(xdgCacheHome, "goreg"), nil
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "goreg"), nil
+}
+
+// Version returns what identifies the goreg build in cache keys: version
+// itself for a release, or the VCS revision a development build was made
+// from. ok is false for a development build that cannot be told apart from
+// others, because it lacks VCS information or was made from a modified
+// tree; such a build must not use the cache.
+func Version(version string) (string, bool) {
+	if version != "" && version != "(devel)" && version != "unknown" {
+		return version, true
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "", false
+	}
+	var revision string
+	for _, setting := range info.Settings {
+		switch {
+		case setting.Key == "vcs.revision":
+			revision = setting.Value
+		case setting.Key == "vcs.modified" && setting.Value == "true":
+			return "", false
+		}
+	}
+	if revision == "" {
+		return "", false
+	}
+	return "(devel)+" + revision, true
+}
+
+// Cache records the content hashes of files goreg already found formatted.
+// A nil *Cache is valid and never reports a hit.
+type Cache struct {
+	dir string
+}
+
+// Open returns the cache stored in dir. The directory is created on the
+// first Put.
+func Open(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Key identifies content formatted with the given settings by the given
+// goreg version.
+func Key(version string, settings string, content []byte) string {
+	h := sha256.New()
+	h.Write([]byte(version))
+	h.Write([]byte{0})
+	h.Write([]byte(settings))
+	h.Write([]byte{0})
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Has reports whether key was recorded.
+func (c *Cache) Has(key string) bool {
+	if c == nil {
+		return false
+	}
+	_, err := os.Stat(c.path(key))
+	return err == nil
+}
+
+// Put records key.
+func (c *Cache) Put(key string) error {
+	if c == nil {
+		return nil
+	}
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, nil, 0644)
+}
+
+// Clean removes every entry stored in dir.
+func Clean(dir string) error {
+	if dir == "" {
+		return errors.New("cache directory is not set")
+	}
+	return os.RemoveAll(dir)
+}
+
+// path shards entries by the first two characters of their key to keep
+// directories small.
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key)
+}
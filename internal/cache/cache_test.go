@@ -0,0 +1,107 @@
+package cache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/magicdrive/goreg/internal/cache"
+)
+
+func TestCache(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "goreg")
+	c := cache.Open(dir)
+
+	key := cache.Key("1.0.0", "settings", []byte("package main\n"))
+	if c.Has(key) {
+		t.Fatalf("expected empty cache to miss")
+	}
+
+	if err := c.Put(key); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if !c.Has(key) {
+		t.Errorf("expected recorded key to hit")
+	}
+
+	if err := cache.Clean(dir); err != nil {
+		t.Fatalf("Clean failed: %v", err)
+	}
+	if c.Has(key) {
+		t.Errorf("expected cleaned cache to miss")
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("expected cache directory to be removed, got %v", err)
+	}
+}
+
+func TestCache_Nil(t *testing.T) {
+	var c *cache.Cache
+	if c.Has("00") {
+		t.Errorf("expected nil cache to miss")
+	}
+	if err := c.Put("00"); err != nil {
+		t.Errorf("expected nil cache Put to be a no-op, got %v", err)
+	}
+}
+
+func TestKey(t *testing.T) {
+	base := cache.Key("1.0.0", "settings", []byte("package main\n"))
+
+	tests := []struct {
+		name     string
+		version  string
+		settings string
+		content  string
+	}{
+		{name: "Different version", version: "1.0.1", settings: "settings", content: "package main\n"},
+		{name: "Different settings", version: "1.0.0", settings: "other", content: "package main\n"},
+		{name: "Different content", version: "1.0.0", settings: "settings", content: "package foo\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cache.Key(tt.version, tt.settings, []byte(tt.content)); got == base {
+				t.Errorf("expected key to change")
+			}
+		})
+	}
+}
+
+func TestDir_XDGCacheHome(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", xdg)
+
+	dir, err := cache.Dir()
+	if err != nil {
+		t.Fatalf("Dir failed: %v", err)
+	}
+	if expected := filepath.Join(xdg, "goreg"); dir != expected {
+		t.Errorf("expected %s, got %s", expected, dir)
+	}
+}
+
+func TestDir(t *testing.T) {
+	xdgDir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", xdgDir)
+
+	dir, err := cache.Dir()
+	if err != nil {
+		t.Fatalf("Dir failed: %v", err)
+	}
+	if expected := filepath.Join(xdgDir, "goreg"); dir != expected {
+		t.Errorf("expected %s, got %s", expected, dir)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	version, ok := cache.Version("v1.2.3")
+	if !ok || version != "v1.2.3" {
+		t.Errorf("expected release version to be used as is, got %q, %v", version, ok)
+	}
+
+	// Test binaries carry no VCS information.
+	if version, ok := cache.Version("(devel)"); ok {
+		t.Errorf("expected development build without VCS information to skip the cache, got %q", version)
+	}
+}
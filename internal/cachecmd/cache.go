@@ -0,0 +1,34 @@
+package cachecmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/magicdrive/goreg/internal/cache"
+)
+
+func Execute(args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("a cache subcommand is required: clean")
+	}
+
+	switch args[0] {
+	case "clean":
+		dir, err := cache.Dir()
+		if err != nil {
+			return err
+		}
+		return Clean(dir, w)
+	default:
+		return fmt.Errorf("unknown cache subcommand: %s", args[0])
+	}
+}
+
+// Clean removes the cache stored in dir and reports it to w.
+func Clean(dir string, w io.Writer) error {
+	if err := cache.Clean(dir); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "removed %s\n", dir)
+	return nil
+}
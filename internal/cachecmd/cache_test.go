@@ -0,0 +1,53 @@
+package cachecmd_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/magicdrive/goreg/internal/cache"
+	"github.com/magicdrive/goreg/internal/cachecmd"
+)
+
+func TestExecute_Clean(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", xdg)
+
+	dir := filepath.Join(xdg, "goreg")
+	key := cache.Key("1.0.0", "", []byte("package main\n"))
+	if err := cache.Open(dir).Put(key); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := cachecmd.Execute([]string{"clean"}, &out); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("expected cache directory to be removed, got %v", err)
+	}
+	if !strings.Contains(out.String(), dir) {
+		t.Errorf("expected output to mention %s, got %q", dir, out.String())
+	}
+}
+
+func TestExecute_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "Missing subcommand", args: []string{}},
+		{name: "Unknown subcommand", args: []string{"purge"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := cachecmd.Execute(tt.args, &bytes.Buffer{}); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
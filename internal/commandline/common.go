@@ -1,15 +0,0 @@
-package commandline
-
-func Unique[T comparable](arr []T) []T {
-	seen := make(map[T]struct{}, len(arr))
-	result := make([]T, 0, len(arr))
-
-	for _, v := range arr {
-		if _, exists := seen[v]; !exists {
-			seen[v] = struct{}{}
-			result = append(result, v)
-		}
-	}
-
-	return result
-}
@@ -0,0 +1,52 @@
+package commandline
+
+import (
+	"strconv"
+	"strings"
+)
+
+// stringsFlag collects the values of a repeatable flag. Each value may also
+// hold several comma-separated entries.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*s = append(*s, v)
+		}
+	}
+	return nil
+}
+
+const defaultBackupSuffix = ".orig"
+
+// backupFlag is a boolean-style flag that optionally takes a suffix:
+// --backup keeps a copy with ".orig" appended, --backup=.bak uses ".bak".
+type backupFlag struct {
+	suffix string
+}
+
+func (b *backupFlag) String() string {
+	return b.suffix
+}
+
+func (b *backupFlag) Set(value string) error {
+	if enabled, err := strconv.ParseBool(value); err == nil {
+		if enabled {
+			b.suffix = defaultBackupSuffix
+		} else {
+			b.suffix = ""
+		}
+		return nil
+	}
+	b.suffix = value
+	return nil
+}
+
+func (b *backupFlag) IsBoolFlag() bool {
+	return true
+}
@@ -4,6 +4,8 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	_ "embed"
 
@@ -18,10 +20,42 @@ func OptParse(args []string) (int, *Option, error) {
 
 	optLength := len(args)
 
-	cfg, _ := common.LoadConfig()
+	configPath, noConfig := scanConfigFlags(args)
+
+	cfg, err := common.LoadConfig(configPath, noConfig)
+	if err != nil {
+		return optLength, nil, err
+	}
 
 	fs := flag.NewFlagSet("goreg", flag.ExitOnError)
 
+	/* ------------------ */
+	/* config file        */
+	/* ------------------ */
+
+	// --config
+	fs.String("config", configPath, "Specify goreg.toml to use.")
+
+	// --no-config
+	fs.Bool("no-config", noConfig, "Do not use goreg.toml.")
+
+	/* ------------------ */
+	/* directory walk     */
+	/* ------------------ */
+
+	// --exclude
+	var excludeOpt stringsFlag
+	fs.Var(&excludeOpt, "exclude", "Exclude files matching the pattern from directory walks.")
+
+	// --no-gitignore
+	noGitignoreOpt := fs.Bool("no-gitignore", cfg.NoGitignore, "Do not skip files ignored by .gitignore.")
+
+	// --include-generated
+	includeGeneratedOpt := fs.Bool("include-generated", cfg.IncludeGenerated, "Format generated files too.")
+
+	// --no-cache
+	noCacheOpt := fs.Bool("no-cache", cfg.NoCache, "Do not use the cache of already formatted files.")
+
 	/* ------------------ */
 	/* cfg Import section */
 	/* ------------------ */
@@ -57,10 +91,51 @@ func OptParse(args []string) (int, *Option, error) {
 		cfg.Format.RemoveImportComment, "Remove the comments in the import.")
 	fs.BoolVar(removeImportCommentOpt, "r", cfg.Format.RemoveImportComment, "Remove the comments in the import.")
 
+	// --remove-redundant-alias
+	removeRedundantAliasOpt := fs.Bool("remove-redundant-alias", cfg.Format.RemoveRedundantAlias,
+		"Drop aliases equal to the package name, like fmt \"fmt\".")
+
+	// --sort
+	sortOpt := fs.String("sort", cfg.Format.Sort,
+		"Sort imports within a group: lexical, case-insensitive, natural, segment, alias or preserve.")
+
+	// --thirdparty-subgroup
+	thirdPartySubgroupOpt := fs.String("thirdparty-subgroup", cfg.Format.ThirdPartySubgroup,
+		"Split third-party imports into blocks: none, host or module.")
+
+	// --group-separator
+	groupSeparatorOpt := fs.String("group-separator", cfg.Format.GroupSeparator,
+		"Separate import groups with a blank line (blank) or nothing (none).")
+
+	// --single-import
+	singleImportOpt := fs.String("single-import", cfg.Format.SingleImport,
+		"Write a lone import as is (preserve), as import \"fmt\" (collapse) or as a block (expand).")
+
+	// --emit-group-headers
+	emitGroupHeadersOpt := fs.Bool("emit-group-headers", cfg.Format.EmitGroupHeaders,
+		"Write a header comment above each import group.")
+
+	// --alias-placement
+	aliasPlacementOpt := fs.String("alias-placement", "",
+		"Place blank, dot and aliased imports, e.g. blank=own-group,alias=inline.")
+
+	// --gofmt
+	gofmtOpt := fs.Bool("gofmt", cfg.Format.Gofmt, "Format the whole file with gofmt before sorting imports.")
+
 	// --write
 	writeFlagOpt := fs.Bool("write", false, "Show help message.")
 	fs.BoolVar(writeFlagOpt, "w", false, "Show help message.")
 
+	// --verify
+	verifyFlagOpt := fs.Bool("verify", false, "Verify that no import or comment is lost or altered.")
+
+	// --check
+	checkFlagOpt := fs.Bool("check", false, "Report imports breaking the [rules] of goreg.toml instead of formatting.")
+
+	// --backup
+	var backupOpt backupFlag
+	fs.Var(&backupOpt, "backup", "Keep a copy of the original file when writing.")
+
 	// --help
 	helpFlagOpt := fs.Bool("help", false, "Show help message.")
 	fs.BoolVar(helpFlagOpt, "h", false, "Show help message.")
@@ -73,11 +148,18 @@ func OptParse(args []string) (int, *Option, error) {
 		fmt.Fprintln(os.Stderr, "\nHelpOption:")
 		fmt.Fprintln(os.Stderr, "    goreg --help")
 	}
-	err := fs.Parse(args)
-	if err != nil {
+	if err := fs.Parse(args); err != nil {
 		return optLength, nil, err
 	}
 
+	// --verify defaults to on in --write mode
+	verifyFlag := *writeFlagOpt
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "verify" {
+			verifyFlag = *verifyFlagOpt
+		}
+	})
+
 	var filename = ""
 	_args := fs.Args()
 	if len(_args) > 0 {
@@ -88,23 +170,78 @@ func OptParse(args []string) (int, *Option, error) {
 	if *orderOpt == "" {
 		_importOrder = model.DefaultOrder
 	} else {
-		_importOrder, err = GenerateOrderStrings(*orderOpt)
+		_importOrder, err = model.GenerateOrderStrings(*orderOpt)
 		if err != nil {
-			return optLength, nil, err
+			return optLength, nil, fmt.Errorf("--order: %w", err)
+		}
+	}
+
+	sortStrategy, err := model.ParseSortStrategy(*sortOpt)
+	if err != nil {
+		return optLength, nil, fmt.Errorf("--sort: %w", err)
+	}
+
+	thirdPartySubgroup, err := model.ParseSubgroupMode(*thirdPartySubgroupOpt)
+	if err != nil {
+		return optLength, nil, fmt.Errorf("--thirdparty-subgroup: %w", err)
+	}
+
+	groupSeparator, err := model.ParseGroupSeparator(*groupSeparatorOpt)
+	if err != nil {
+		return optLength, nil, fmt.Errorf("--group-separator: %w", err)
+	}
+
+	singleImport, err := model.ParseSingleImport(*singleImportOpt)
+	if err != nil {
+		return optLength, nil, fmt.Errorf("--single-import: %w", err)
+	}
+
+	aliasPlacement, err := model.ParseAliasPlacement(*aliasPlacementOpt)
+	if err != nil {
+		return optLength, nil, fmt.Errorf("--alias-placement: %w", err)
+	}
+
+	for _, pattern := range excludeOpt {
+		if err := model.ValidateGlob(pattern); err != nil {
+			return optLength, nil, fmt.Errorf("--exclude: %w", err)
 		}
 	}
 
 	result := &Option{
-		ImportOrder:          _importOrder,
-		OrganizationName:     *organizationOpt,
-		MinimizeGroupFlag:    *minimizeGroupOpt,
-		SortIncludeAliasFlag: *sortIncludeAliasOpt,
-		WriteFlag:            *writeFlagOpt,
-		HelpFlag:             *helpFlagOpt,
-		VersionFlag:          *versionFlagOpt,
-		ModulePath:           *modulePathOpt,
-		FileName:             filename,
-		FlagSet:              fs,
+		ImportOrder:              _importOrder,
+		OrganizationName:         *organizationOpt,
+		RemoveImportCommentFlag:  *removeImportCommentOpt,
+		RemoveRedundantAliasFlag: *removeRedundantAliasOpt,
+		Aliases:                  cfg.Aliases,
+		Rules:                    cfg.Rules,
+		CheckFlag:                *checkFlagOpt,
+		MinimizeGroupFlag:        *minimizeGroupOpt,
+		SortIncludeAliasFlag:     *sortIncludeAliasOpt,
+		SortStrategy:             sortStrategy,
+		Pinned:                   cfg.Format.Pinned,
+		PinnedBottom:             cfg.Format.PinnedBottom,
+		ThirdPartySubgroup:       thirdPartySubgroup,
+		GroupSeparator:           groupSeparator,
+		SingleImport:             singleImport,
+		EmitGroupHeadersFlag:     *emitGroupHeadersOpt,
+		GroupHeaders:             cfg.Format.GroupHeaders,
+		AliasPlacement:           cfg.Format.AliasPlacement.Merge(aliasPlacement),
+		GofmtFlag:                *gofmtOpt,
+		WriteFlag:                *writeFlagOpt,
+		BackupSuffix:             backupOpt.suffix,
+		VerifyFlag:               verifyFlag,
+		HelpFlag:                 *helpFlagOpt,
+		VersionFlag:              *versionFlagOpt,
+		ModulePath:               *modulePathOpt,
+		FileName:                 filename,
+		ConfigPath:               cfg.FilePath,
+		NoConfigFlag:             noConfig,
+		Overrides:                cfg.Overrides,
+		Exclude:                  append(cfg.Exclude, excludeOpt...),
+		NoGitignoreFlag:          *noGitignoreOpt,
+		IncludeGeneratedFlag:     *includeGeneratedOpt,
+		NoCacheFlag:              *noCacheOpt,
+		FlagSet:                  fs,
 	}
 
 	OverRideHelp(fs)
@@ -112,6 +249,42 @@ func OptParse(args []string) (int, *Option, error) {
 	return optLength, result, nil
 }
 
+// scanConfigFlags picks --config and --no-config out of args ahead of the
+// real parse, because the config they select provides the flag defaults.
+func scanConfigFlags(args []string) (string, bool) {
+	var configPath string
+	var noConfig bool
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		switch name {
+		case "config":
+			if hasValue {
+				configPath = value
+			} else if i+1 < len(args) {
+				i++
+				configPath = args[i]
+			}
+		case "no-config":
+			if !hasValue {
+				noConfig = true
+			} else if v, err := strconv.ParseBool(value); err == nil {
+				noConfig = v
+			}
+		}
+	}
+
+	return configPath, noConfig
+}
+
 func OverRideHelp(fs *flag.FlagSet) *flag.FlagSet {
 	fs.Usage = func() {
 		fmt.Print(helpMessage)
@@ -2,6 +2,7 @@ package commandline_test
 
 import (
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -255,6 +256,7 @@ func TestOptParse(t *testing.T) {
 				MinimizeGroupFlag:    false,
 				SortIncludeAliasFlag: false,
 				WriteFlag:            true,
+				VerifyFlag:           true,
 				HelpFlag:             false,
 				VersionFlag:          false,
 				ModulePath:           "",
@@ -262,6 +264,183 @@ func TestOptParse(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Disable config file",
+			args: []string{"--no-config", "main.go"},
+			expected: &commandline.Option{
+				ImportOrder:          model.DefaultOrder,
+				OrganizationName:     "",
+				MinimizeGroupFlag:    false,
+				SortIncludeAliasFlag: false,
+				WriteFlag:            false,
+				HelpFlag:             false,
+				VersionFlag:          false,
+				ModulePath:           "",
+				FileName:             "main.go",
+				NoConfigFlag:         true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Enable backup with default suffix",
+			args: []string{"-w", "--backup", "main.go"},
+			expected: &commandline.Option{
+				ImportOrder:  model.DefaultOrder,
+				WriteFlag:    true,
+				BackupSuffix: ".orig",
+				VerifyFlag:   true,
+				FileName:     "main.go",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Enable backup with custom suffix",
+			args: []string{"-w", "--backup=.bak", "main.go"},
+			expected: &commandline.Option{
+				ImportOrder:  model.DefaultOrder,
+				WriteFlag:    true,
+				BackupSuffix: ".bak",
+				VerifyFlag:   true,
+				FileName:     "main.go",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Disable verify in write mode",
+			args: []string{"-w", "--verify=false", "main.go"},
+			expected: &commandline.Option{
+				ImportOrder: model.DefaultOrder,
+				WriteFlag:   true,
+				VerifyFlag:  false,
+				FileName:    "main.go",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Enable verify without write mode",
+			args: []string{"--verify", "main.go"},
+			expected: &commandline.Option{
+				ImportOrder: model.DefaultOrder,
+				VerifyFlag:  true,
+				FileName:    "main.go",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Specify sort strategy",
+			args: []string{"--sort", "natural", "main.go"},
+			expected: &commandline.Option{
+				ImportOrder:  model.DefaultOrder,
+				SortStrategy: model.SortNatural,
+				FileName:     "main.go",
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Invalid sort strategy",
+			args:     []string{"--sort", "random", "main.go"},
+			expected: nil,
+			wantErr:  true,
+		},
+		{
+			name: "Specify thirdparty subgroup",
+			args: []string{"--thirdparty-subgroup", "host", "main.go"},
+			expected: &commandline.Option{
+				ImportOrder:        model.DefaultOrder,
+				ThirdPartySubgroup: model.SubgroupHost,
+				FileName:           "main.go",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Check mode",
+			args: []string{"--check", "."},
+			expected: &commandline.Option{
+				ImportOrder: model.DefaultOrder,
+				CheckFlag:   true,
+				FileName:    ".",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Remove redundant aliases",
+			args: []string{"--remove-redundant-alias", "main.go"},
+			expected: &commandline.Option{
+				ImportOrder:              model.DefaultOrder,
+				RemoveRedundantAliasFlag: true,
+				FileName:                 "main.go",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Specify group layout",
+			args: []string{"--group-separator", "none", "--single-import", "collapse", "main.go"},
+			expected: &commandline.Option{
+				ImportOrder:    model.DefaultOrder,
+				GroupSeparator: model.SeparatorNone,
+				SingleImport:   model.SingleCollapse,
+				FileName:       "main.go",
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Invalid single import mode",
+			args:     []string{"--single-import", "squash", "main.go"},
+			expected: nil,
+			wantErr:  true,
+		},
+		{
+			name: "Emit group headers",
+			args: []string{"--emit-group-headers", "main.go"},
+			expected: &commandline.Option{
+				ImportOrder:          model.DefaultOrder,
+				EmitGroupHeadersFlag: true,
+				FileName:             "main.go",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Specify alias placement",
+			args: []string{"--alias-placement", "blank=own-group,alias=inline", "main.go"},
+			expected: &commandline.Option{
+				ImportOrder:    model.DefaultOrder,
+				AliasPlacement: model.AliasPlacementConfig{Blank: "own-group", Alias: "inline"},
+				FileName:       "main.go",
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Invalid alias placement",
+			args:     []string{"--alias-placement", "blank=first", "main.go"},
+			expected: nil,
+			wantErr:  true,
+		},
+		{
+			name: "Specify exclude patterns",
+			args: []string{"--exclude", "gen/**", "--exclude", "*_mock.go", "main.go"},
+			expected: &commandline.Option{
+				ImportOrder: model.DefaultOrder,
+				Exclude:     []string{"gen/**", "*_mock.go"},
+				FileName:    "main.go",
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Invalid exclude pattern",
+			args:     []string{"--exclude", "gen/[a-", "main.go"},
+			expected: nil,
+			wantErr:  true,
+		},
+		{
+			name: "Enable gofmt flag",
+			args: []string{"--gofmt", "main.go"},
+			expected: &commandline.Option{
+				ImportOrder: model.DefaultOrder,
+				GofmtFlag:   true,
+				FileName:    "main.go",
+			},
+			wantErr: false,
+		},
 		{
 			name: "Enable help flag",
 			args: []string{"--help"},
@@ -322,3 +501,99 @@ func TestOptParse(t *testing.T) {
 		})
 	}
 }
+
+func TestOptParse_ConfigFlag(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "custom.toml")
+	content := `
+[import]
+organization_module = "github.com/fromconfig"
+order = "std,local,thirdparty,organization"
+
+[format]
+minimize_group = true
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	t.Setenv("GOREG_NOT_USE_CONFIGFILE", "1")
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected *commandline.Option
+		wantErr  bool
+	}{
+		{
+			name: "Explicit config file",
+			args: []string{"--config", configPath, "main.go"},
+			expected: &commandline.Option{
+				ImportOrder:       []model.ImportGroup{model.StdLib, model.Local, model.ThirdParty, model.Organization},
+				OrganizationName:  "github.com/fromconfig",
+				MinimizeGroupFlag: true,
+				FileName:          "main.go",
+				ConfigPath:        configPath,
+			},
+		},
+		{
+			name: "Flags override explicit config file",
+			args: []string{"-n", "github.com/fromflag", "--config=" + configPath, "main.go"},
+			expected: &commandline.Option{
+				ImportOrder:       []model.ImportGroup{model.StdLib, model.Local, model.ThirdParty, model.Organization},
+				OrganizationName:  "github.com/fromflag",
+				MinimizeGroupFlag: true,
+				FileName:          "main.go",
+				ConfigPath:        configPath,
+			},
+		},
+		{
+			name: "No config wins over explicit config file",
+			args: []string{"--config", configPath, "--no-config", "main.go"},
+			expected: &commandline.Option{
+				ImportOrder:  model.DefaultOrder,
+				FileName:     "main.go",
+				NoConfigFlag: true,
+			},
+		},
+		{
+			name:    "Missing config file",
+			args:    []string{"--config", filepath.Join(t.TempDir(), "missing.toml"), "main.go"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, err := commandline.OptParse(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error status: got %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got.FlagSet = nil
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestOptParse_EnvOverrides(t *testing.T) {
+	t.Setenv("GOREG_NOT_USE_CONFIGFILE", "1")
+	t.Setenv("GOREG_ORGANIZATION_MODULE", "github.com/fromenv")
+	t.Setenv("GOREG_MINIMIZE_GROUP", "true")
+
+	_, got, err := commandline.OptParse([]string{"-n", "github.com/fromflag", "main.go"})
+	if err != nil {
+		t.Fatalf("OptParse failed: %v", err)
+	}
+
+	if got.OrganizationName != "github.com/fromflag" {
+		t.Errorf("expected flag to override env, got %s", got.OrganizationName)
+	}
+	if !got.MinimizeGroupFlag {
+		t.Error("expected MinimizeGroupFlag from GOREG_MINIMIZE_GROUP")
+	}
+}
@@ -2,20 +2,251 @@ package commandline
 
 import (
 	"flag"
+	"fmt"
+	"path/filepath"
 
+	"github.com/magicdrive/goreg/internal/common"
 	"github.com/magicdrive/goreg/internal/model"
 )
 
 type Option struct {
-	ImportOrder             []model.ImportGroup
-	OrganizationName        string
-	RemoveImportCommentFlag bool
-	MinimizeGroupFlag       bool
-	SortIncludeAliasFlag    bool
-	WriteFlag               bool
-	HelpFlag                bool
-	VersionFlag             bool
-	FileName                string
-	ModulePath              string
-	FlagSet                 *flag.FlagSet
+	ImportOrder              []model.ImportGroup
+	OrganizationName         string
+	RemoveImportCommentFlag  bool
+	RemoveRedundantAliasFlag bool
+	Aliases                  map[string]string
+	Rules                    model.RulesConfig
+	CheckFlag                bool
+	ModuleRoot               string
+	MinimizeGroupFlag        bool
+	SortIncludeAliasFlag     bool
+	SortStrategy             model.SortStrategy
+	Pinned                   []string
+	PinnedBottom             []string
+	ThirdPartySubgroup       model.SubgroupMode
+	Requires                 []string
+	GroupSeparator           model.GroupSeparator
+	SingleImport             model.SingleImport
+	EmitGroupHeadersFlag     bool
+	GroupHeaders             model.GroupHeadersConfig
+	AliasPlacement           model.AliasPlacementConfig
+	GofmtFlag                bool
+	WriteFlag                bool
+	BackupSuffix             string
+	VerifyFlag               bool
+	HelpFlag                 bool
+	VersionFlag              bool
+	FileName                 string
+	ModulePath               string
+	ConfigPath               string
+	NoConfigFlag             bool
+	Overrides                []model.OverrideConfig
+	Exclude                  []string
+	NoGitignoreFlag          bool
+	IncludeGeneratedFlag     bool
+	NoCacheFlag              bool
+	CacheDir                 string
+	Version                  string
+	FlagSet                  *flag.FlagSet
+}
+
+// Fingerprint describes every option that can change how a file is
+// formatted, for use in cache keys. Options that only select files or
+// control how output is written are left out.
+func (o *Option) Fingerprint() string {
+	settings := *o
+	settings.WriteFlag = false
+	settings.BackupSuffix = ""
+	settings.VerifyFlag = false
+	settings.Rules = model.RulesConfig{}
+	settings.CheckFlag = false
+	settings.ModuleRoot = ""
+	settings.HelpFlag = false
+	settings.VersionFlag = false
+	settings.FileName = ""
+	settings.ConfigPath = ""
+	settings.NoConfigFlag = false
+	settings.Overrides = nil
+	settings.Exclude = nil
+	settings.NoGitignoreFlag = false
+	settings.NoCacheFlag = false
+	settings.CacheDir = ""
+	settings.Version = ""
+	settings.FlagSet = nil
+	// Requires only matter to module subgroups and to the package names
+	// [aliases] and remove_redundant_alias rely on.
+	if settings.ThirdPartySubgroup != model.SubgroupModule && len(settings.Aliases) == 0 && !settings.RemoveRedundantAliasFlag {
+		settings.Requires = nil
+	}
+	return fmt.Sprintf("%#v", settings)
+}
+
+// ForFile returns the options to use for filename: a copy of o with every
+// matching [[overrides]] entry applied in order. Patterns are matched against
+// the path relative to the directory of the goreg.toml they came from.
+// Options set by a GOREG_* variable or given explicitly on the command line
+// are never overridden.
+func (o *Option) ForFile(filename string) (*Option, error) {
+	if len(o.Overrides) == 0 {
+		return o, nil
+	}
+
+	name, err := o.relativeToConfig(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	explicit := make(map[string]bool)
+	if o.FlagSet != nil {
+		o.FlagSet.Visit(func(f *flag.Flag) {
+			explicit[f.Name] = true
+		})
+	}
+	isExplicit := func(names ...string) bool {
+		for _, n := range names {
+			if explicit[n] {
+				return true
+			}
+		}
+		return false
+	}
+
+	// GOREG_* variables take precedence over goreg.toml, overrides included.
+	envSet := common.EnvSetKeys()
+	isFixed := func(key string, flags ...string) bool {
+		return envSet[key] || isExplicit(flags...)
+	}
+
+	result := *o
+	for _, override := range o.Overrides {
+		if !matchAny(override.Files, name) {
+			continue
+		}
+
+		if v := override.Import.Order; v != nil && !isFixed("import.order", "order", "o") {
+			order, err := model.GenerateOrderStrings(*v)
+			if err != nil {
+				return nil, fmt.Errorf("overrides: %w", err)
+			}
+			result.ImportOrder = order
+		}
+		if v := override.Import.OrganizationModule; v != nil && !isFixed("import.organization_module", "organization", "n") {
+			result.OrganizationName = *v
+		}
+		if v := override.Format.MinimizeGroup; v != nil && !isFixed("format.minimize_group", "minimize-group", "m") {
+			result.MinimizeGroupFlag = *v
+		}
+		if v := override.Format.SortIncludeAlias; v != nil && !isFixed("format.sort_include_alias", "sort-include-alias", "a") {
+			result.SortIncludeAliasFlag = *v
+		}
+		if v := override.Format.RemoveImportComment; v != nil && !isFixed("format.remove_import_comment", "remove-import-comment", "r") {
+			result.RemoveImportCommentFlag = *v
+		}
+		if v := override.Format.RemoveRedundantAlias; v != nil && !isFixed("format.remove_redundant_alias", "remove-redundant-alias") {
+			result.RemoveRedundantAliasFlag = *v
+		}
+		if v := override.Format.Sort; v != nil && !isFixed("format.sort", "sort") {
+			strategy, err := model.ParseSortStrategy(*v)
+			if err != nil {
+				return nil, fmt.Errorf("overrides: %w", err)
+			}
+			result.SortStrategy = strategy
+		}
+		if v := override.Format.ThirdPartySubgroup; v != nil && !isFixed("format.thirdparty_subgroup", "thirdparty-subgroup") {
+			mode, err := model.ParseSubgroupMode(*v)
+			if err != nil {
+				return nil, fmt.Errorf("overrides: %w", err)
+			}
+			result.ThirdPartySubgroup = mode
+		}
+		if v := override.Format.GroupSeparator; v != nil && !isFixed("format.group_separator", "group-separator") {
+			separator, err := model.ParseGroupSeparator(*v)
+			if err != nil {
+				return nil, fmt.Errorf("overrides: %w", err)
+			}
+			result.GroupSeparator = separator
+		}
+		if v := override.Format.SingleImport; v != nil && !isFixed("format.single_import", "single-import") {
+			mode, err := model.ParseSingleImport(*v)
+			if err != nil {
+				return nil, fmt.Errorf("overrides: %w", err)
+			}
+			result.SingleImport = mode
+		}
+		if v := override.Format.EmitGroupHeaders; v != nil && !isFixed("format.emit_group_headers", "emit-group-headers") {
+			result.EmitGroupHeadersFlag = *v
+		}
+		if v := override.Format.Pinned; v != nil && !isFixed("format.pinned") {
+			result.Pinned = *v
+		}
+		if v := override.Format.PinnedBottom; v != nil && !isFixed("format.pinned_bottom") {
+			result.PinnedBottom = *v
+		}
+		placement := override.Format.AliasPlacement
+		if isFixed("format.alias_placement.blank") {
+			placement.Blank = ""
+		}
+		if isFixed("format.alias_placement.dot") {
+			placement.Dot = ""
+		}
+		if isFixed("format.alias_placement.alias") {
+			placement.Alias = ""
+		}
+		result.AliasPlacement = result.AliasPlacement.Merge(placement)
+	}
+
+	if isExplicit("alias-placement") {
+		placement, err := model.ParseAliasPlacement(o.FlagSet.Lookup("alias-placement").Value.String())
+		if err != nil {
+			return nil, err
+		}
+		result.AliasPlacement = result.AliasPlacement.Merge(placement)
+	}
+
+	return &result, nil
+}
+
+// IsExcluded reports whether path matches one of the exclude patterns,
+// which are relative to the directory of goreg.toml like overrides.
+func (o *Option) IsExcluded(path string) bool {
+	if len(o.Exclude) == 0 {
+		return false
+	}
+
+	name, err := o.relativeToConfig(path)
+	if err != nil {
+		return false
+	}
+	return matchAny(o.Exclude, name)
+}
+
+func (o *Option) relativeToConfig(filename string) (string, error) {
+	baseDir := "."
+	if o.ConfigPath != "" {
+		baseDir = filepath.Dir(o.ConfigPath)
+	}
+
+	absBase, err := filepath.Abs(baseDir)
+	if err != nil {
+		return "", err
+	}
+	absFile, err := filepath.Abs(filename)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(absBase, absFile)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if common.MatchGlob(pattern, name) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,141 @@
+package commandline_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/magicdrive/goreg/internal/commandline"
+	"github.com/magicdrive/goreg/internal/model"
+)
+
+func TestForFile(t *testing.T) {
+	configDir := t.TempDir()
+	configPath := filepath.Join(configDir, "goreg.toml")
+	content := `
+[import]
+organization_module = "github.com/myorg"
+
+[[overrides]]
+files = ["**/*_test.go"]
+[overrides.import]
+order = "std,local,thirdparty,organization"
+[overrides.format]
+minimize_group = true
+
+[[overrides]]
+files = ["internal/gen/**"]
+[overrides.import]
+organization_module = ""
+[overrides.format]
+remove_import_comment = true
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	t.Setenv("GOREG_NOT_USE_CONFIGFILE", "1")
+
+	tests := []struct {
+		name                string
+		args                []string
+		env                 map[string]string
+		file                string
+		order               []model.ImportGroup
+		organization        string
+		minimizeGroup       bool
+		removeImportComment bool
+	}{
+		{
+			name:         "No override matches",
+			file:         filepath.Join(configDir, "main.go"),
+			order:        model.DefaultOrder,
+			organization: "github.com/myorg",
+		},
+		{
+			name:          "Test file override",
+			file:          filepath.Join(configDir, "internal", "core", "importer_test.go"),
+			order:         []model.ImportGroup{model.StdLib, model.Local, model.ThirdParty, model.Organization},
+			organization:  "github.com/myorg",
+			minimizeGroup: true,
+		},
+		{
+			name:                "Both overrides match in order",
+			file:                filepath.Join(configDir, "internal", "gen", "model_test.go"),
+			order:               []model.ImportGroup{model.StdLib, model.Local, model.ThirdParty, model.Organization},
+			organization:        "",
+			minimizeGroup:       true,
+			removeImportComment: true,
+		},
+		{
+			name:          "Explicit flags win over overrides",
+			args:          []string{"-o", "std,thirdparty,organization,local", "--minimize-group=false"},
+			file:          filepath.Join(configDir, "main_test.go"),
+			order:         model.DefaultOrder,
+			organization:  "github.com/myorg",
+			minimizeGroup: false,
+		},
+		{
+			name:          "Environment variables win over overrides",
+			env:           map[string]string{"GOREG_ORDER": "std,thirdparty,organization,local", "GOREG_MINIMIZE_GROUP": "false"},
+			file:          filepath.Join(configDir, "main_test.go"),
+			order:         model.DefaultOrder,
+			organization:  "github.com/myorg",
+			minimizeGroup: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			args := append([]string{"--config", configPath}, tt.args...)
+			_, opt, err := commandline.OptParse(append(args, tt.file))
+			if err != nil {
+				t.Fatalf("OptParse failed: %v", err)
+			}
+
+			got, err := opt.ForFile(tt.file)
+			if err != nil {
+				t.Fatalf("ForFile failed: %v", err)
+			}
+
+			if !reflect.DeepEqual(got.ImportOrder, tt.order) {
+				t.Errorf("ImportOrder: expected %v, got %v", tt.order, got.ImportOrder)
+			}
+			if got.OrganizationName != tt.organization {
+				t.Errorf("OrganizationName: expected %q, got %q", tt.organization, got.OrganizationName)
+			}
+			if got.MinimizeGroupFlag != tt.minimizeGroup {
+				t.Errorf("MinimizeGroupFlag: expected %v, got %v", tt.minimizeGroup, got.MinimizeGroupFlag)
+			}
+			if got.RemoveImportCommentFlag != tt.removeImportComment {
+				t.Errorf("RemoveImportCommentFlag: expected %v, got %v", tt.removeImportComment, got.RemoveImportCommentFlag)
+			}
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	base := &commandline.Option{
+		ImportOrder: model.DefaultOrder,
+		ModulePath:  "myproject/module",
+	}
+
+	runOnly := *base
+	runOnly.WriteFlag = true
+	runOnly.FileName = "main.go"
+	runOnly.CacheDir = "/tmp/goreg"
+	if runOnly.Fingerprint() != base.Fingerprint() {
+		t.Errorf("expected options that do not affect formatting to be ignored")
+	}
+
+	changed := *base
+	changed.MinimizeGroupFlag = true
+	if changed.Fingerprint() == base.Fingerprint() {
+		t.Errorf("expected formatting options to change the fingerprint")
+	}
+}
@@ -0,0 +1,165 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+
+	"github.com/magicdrive/goreg/internal/model"
+)
+
+// ConfigError is a goreg.toml problem located at Line and Column of Path.
+// Line and Column are 1-based; zero means the location is unknown.
+type ConfigError struct {
+	Path    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *ConfigError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.Path, e.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", e.Path, e.Line, e.Column, e.Message)
+}
+
+// wrapDecodeError converts errors returned by the TOML decoder into
+// ConfigErrors carrying the location reported by the decoder.
+func wrapDecodeError(filePath string, err error) error {
+	var strictErr *toml.StrictMissingError
+	if errors.As(err, &strictErr) {
+		errs := make([]error, 0, len(strictErr.Errors))
+		for _, e := range strictErr.Errors {
+			line, col := e.Position()
+			errs = append(errs, &ConfigError{
+				Path:    filePath,
+				Line:    line,
+				Column:  col,
+				Message: fmt.Sprintf("unknown key %q", strings.Join(e.Key(), ".")),
+			})
+		}
+		return errors.Join(errs...)
+	}
+
+	var decodeErr *toml.DecodeError
+	if errors.As(err, &decodeErr) {
+		line, col := decodeErr.Position()
+		return &ConfigError{
+			Path:    filePath,
+			Line:    line,
+			Column:  col,
+			Message: strings.TrimPrefix(decodeErr.Error(), "toml: "),
+		}
+	}
+
+	return &ConfigError{Path: filePath, Message: err.Error()}
+}
+
+// wrapValidateError attaches the location of each offending key to the
+// *model.FieldError values joined in err.
+func wrapValidateError(filePath string, data []byte, err error) error {
+	var fieldErrs []*model.FieldError
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			var fe *model.FieldError
+			if errors.As(e, &fe) {
+				fieldErrs = append(fieldErrs, fe)
+			}
+		}
+	} else {
+		var fe *model.FieldError
+		if errors.As(err, &fe) {
+			fieldErrs = append(fieldErrs, fe)
+		}
+	}
+
+	if len(fieldErrs) == 0 {
+		return &ConfigError{Path: filePath, Message: err.Error()}
+	}
+
+	errs := make([]error, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		line, col := locateKey(data, fe.Key)
+		errs = append(errs, &ConfigError{
+			Path:    filePath,
+			Line:    line,
+			Column:  col,
+			Message: fe.Error(),
+		})
+	}
+	return errors.Join(errs...)
+}
+
+// splitErrors returns the errors joined in err, or err alone.
+func splitErrors(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+// locateKey returns the position of the key whose full dotted path is key.
+// Entries of an array of tables are addressed by index, e.g.
+// overrides.0.import.order. It returns 0, 0 if the key does not appear in
+// data.
+func locateKey(data []byte, key []string) (int, int) {
+	want := strings.Join(key, ".")
+
+	p := unstable.Parser{}
+	p.Reset(data)
+
+	arrayIndex := make(map[string]int)
+	var table []string
+	for p.NextExpression() {
+		expr := p.Expression()
+
+		var parts []string
+		var first *unstable.Node
+		it := expr.Key()
+		for it.Next() {
+			if first == nil {
+				first = it.Node()
+			}
+			parts = append(parts, string(it.Node().Data))
+		}
+
+		switch expr.Kind {
+		case unstable.ArrayTable:
+			name := strings.Join(parts, ".")
+			if _, ok := arrayIndex[name]; ok {
+				arrayIndex[name]++
+			} else {
+				arrayIndex[name] = 0
+			}
+			table = indexedKey(parts, arrayIndex)
+		case unstable.Table:
+			table = indexedKey(parts, arrayIndex)
+		case unstable.KeyValue:
+			full := append(append([]string{}, table...), parts...)
+			if strings.Join(full, ".") == want {
+				pos := p.Shape(first.Raw).Start
+				return pos.Line, pos.Column
+			}
+		}
+	}
+
+	return 0, 0
+}
+
+// indexedKey inserts the current index after every prefix of parts that
+// names an array of tables.
+func indexedKey(parts []string, arrayIndex map[string]int) []string {
+	result := make([]string, 0, len(parts)+1)
+	for i, part := range parts {
+		result = append(result, part)
+		if idx, ok := arrayIndex[strings.Join(parts[:i+1], ".")]; ok {
+			result = append(result, strconv.Itoa(idx))
+		}
+	}
+	return result
+}
@@ -0,0 +1,158 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/magicdrive/goreg/internal/model"
+)
+
+const envPrefix = "GOREG_"
+
+// envBinding ties a goreg.toml key to the environment variable overriding it.
+type envBinding struct {
+	Name  string
+	Key   []string
+	Field reflect.Value
+}
+
+// EnvSetKeys returns the dotted goreg.toml keys, like "format.sort", whose
+// GOREG_* environment variable is set to a non-empty value.
+func EnvSetKeys() map[string]bool {
+	keys := make(map[string]bool)
+	for _, b := range envBindings(&model.Config{}) {
+		if os.Getenv(b.Name) != "" {
+			keys[strings.Join(b.Key, ".")] = true
+		}
+	}
+	return keys
+}
+
+// ApplyEnv overwrites every key of cfg whose GOREG_* environment variable is
+// set to a non-empty value. Variable names are derived from the toml tags of
+// model.Config: the top-level section is dropped and the remaining key path
+// is upper-cased and joined with "_", e.g. [import] order is GOREG_ORDER.
+// Only string, boolean, integer and string list keys are bound; maps and
+// arrays of tables such as [aliases] and [[overrides]] are not.
+func ApplyEnv(cfg *model.Config) error {
+	bindings := envBindings(cfg)
+	names := make(map[string]string, len(bindings))
+
+	var errs []error
+	for _, b := range bindings {
+		names[strings.Join(b.Key, ".")] = b.Name
+
+		raw := os.Getenv(b.Name)
+		if raw == "" {
+			continue
+		}
+		if err := setFromEnv(b.Field, raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", b.Name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		var fieldErrs []error
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			fieldErrs = joined.Unwrap()
+		} else {
+			fieldErrs = []error{err}
+		}
+
+		for _, e := range fieldErrs {
+			var fe *model.FieldError
+			if errors.As(e, &fe) {
+				if name, ok := names[strings.Join(fe.Key, ".")]; ok && os.Getenv(name) != "" {
+					errs = append(errs, fmt.Errorf("%s: %w", name, fe.Err))
+					continue
+				}
+			}
+			errs = append(errs, e)
+		}
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
+
+func envBindings(cfg *model.Config) []envBinding {
+	var result []envBinding
+	collectEnvBindings(reflect.ValueOf(cfg).Elem(), nil, &result)
+	return result
+}
+
+func collectEnvBindings(v reflect.Value, key []string, result *[]envBinding) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("toml"), ",")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		fieldKey := append(append([]string{}, key...), tag)
+		if field.Type.Kind() == reflect.Struct {
+			collectEnvBindings(v.Field(i), fieldKey, result)
+			continue
+		}
+		if !isEnvSupported(field.Type) {
+			continue
+		}
+
+		nameKey := fieldKey
+		if len(nameKey) > 1 {
+			nameKey = nameKey[1:]
+		}
+		*result = append(*result, envBinding{
+			Name:  envPrefix + strings.ToUpper(strings.Join(nameKey, "_")),
+			Key:   fieldKey,
+			Field: v.Field(i),
+		})
+	}
+}
+
+func isEnvSupported(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	default:
+		return false
+	}
+}
+
+func setFromEnv(field reflect.Value, raw string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		field.SetInt(int64(n))
+	case reflect.Slice:
+		var values []string
+		for _, s := range strings.Split(raw, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				values = append(values, s)
+			}
+		}
+		field.Set(reflect.ValueOf(values))
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/magicdrive/goreg/internal/model"
+)
+
+func TestEnvNames(t *testing.T) {
+	expected := []string{
+		"GOREG_EXCLUDE",
+		"GOREG_NO_GITIGNORE",
+		"GOREG_INCLUDE_GENERATED",
+		"GOREG_NO_CACHE",
+		"GOREG_LOCAL_MODULE",
+		"GOREG_ORGANIZATION_MODULE",
+		"GOREG_ORDER",
+		"GOREG_MINIMIZE_GROUP",
+		"GOREG_SORT_INCLUDE_ALIAS",
+		"GOREG_REMOVE_IMPORT_COMMENT",
+		"GOREG_REMOVE_REDUNDANT_ALIAS",
+		"GOREG_GOFMT",
+		"GOREG_SORT",
+		"GOREG_PINNED",
+		"GOREG_PINNED_BOTTOM",
+		"GOREG_THIRDPARTY_SUBGROUP",
+		"GOREG_GROUP_SEPARATOR",
+		"GOREG_SINGLE_IMPORT",
+		"GOREG_EMIT_GROUP_HEADERS",
+		"GOREG_GROUP_HEADERS_STD",
+		"GOREG_GROUP_HEADERS_THIRDPARTY",
+		"GOREG_GROUP_HEADERS_ORGANIZATION",
+		"GOREG_GROUP_HEADERS_LOCAL",
+		"GOREG_GROUP_HEADERS_BLANK",
+		"GOREG_GROUP_HEADERS_DOT",
+		"GOREG_GROUP_HEADERS_ALIAS",
+		"GOREG_ALIAS_PLACEMENT_BLANK",
+		"GOREG_ALIAS_PLACEMENT_DOT",
+		"GOREG_ALIAS_PLACEMENT_ALIAS",
+		"GOREG_DENY",
+	}
+
+	var names []string
+	for _, b := range envBindings(&model.Config{}) {
+		names = append(names, b.Name)
+	}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			t.Errorf("environment variable %s is bound to more than one key", name)
+		}
+		seen[name] = true
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	t.Setenv("GOREG_ORDER", "std,local,thirdparty,organization")
+	t.Setenv("GOREG_LOCAL_MODULE", "example.com/local")
+	t.Setenv("GOREG_MINIMIZE_GROUP", "true")
+	t.Setenv("GOREG_REMOVE_IMPORT_COMMENT", "")
+
+	cfg := &model.Config{}
+	cfg.Format.RemoveImportComment = true
+	cfg.SetDefaults()
+
+	if err := ApplyEnv(cfg); err != nil {
+		t.Fatalf("ApplyEnv failed: %v", err)
+	}
+
+	if cfg.Import.Order != "std,local,thirdparty,organization" {
+		t.Errorf("expected order from GOREG_ORDER, got %s", cfg.Import.Order)
+	}
+	if cfg.Import.LocalModule != "example.com/local" {
+		t.Errorf("expected local_module from GOREG_LOCAL_MODULE, got %s", cfg.Import.LocalModule)
+	}
+	if !cfg.Format.MinimizeGroup {
+		t.Error("expected minimize_group to be true from GOREG_MINIMIZE_GROUP")
+	}
+	if !cfg.Format.RemoveImportComment {
+		t.Error("expected empty GOREG_REMOVE_IMPORT_COMMENT to be ignored")
+	}
+}
+
+func TestApplyEnv_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		value    string
+		contains string
+	}{
+		{name: "Invalid boolean", env: "GOREG_MINIMIZE_GROUP", value: "maybe", contains: "GOREG_MINIMIZE_GROUP: invalid boolean"},
+		{name: "Invalid order", env: "GOREG_ORDER", value: "std,local", contains: "GOREG_ORDER: order must include"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.env, tt.value)
+
+			cfg := &model.Config{}
+			cfg.SetDefaults()
+
+			err := ApplyEnv(cfg)
+			if err == nil {
+				t.Fatal("expected error, but got nil")
+			}
+			if !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("expected error to contain %q, got %q", tt.contains, err.Error())
+			}
+		})
+	}
+}
+
+func TestLoadConfig_EnvOverridesToml(t *testing.T) {
+	tempFile := filepath.Join(t.TempDir(), "goreg.toml")
+	content := `[import]
+organization_module = "github.com/fromtoml"
+local_module = "example.com/fromtoml"
+`
+	if err := os.WriteFile(tempFile, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to create temp goreg.toml: %v", err)
+	}
+
+	t.Setenv("GOREG_ORGANIZATION_MODULE", "github.com/fromenv")
+
+	cfg, err := LoadConfig(tempFile, false)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Import.OrganizationModule != "github.com/fromenv" {
+		t.Errorf("expected env to override toml, got %s", cfg.Import.OrganizationModule)
+	}
+	if cfg.Import.LocalModule != "example.com/fromtoml" {
+		t.Errorf("expected toml value to be kept, got %s", cfg.Import.LocalModule)
+	}
+}
+
+func TestEnvNames_README(t *testing.T) {
+	readme, err := os.ReadFile(filepath.Join("..", "..", "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	section := string(readme)
+	if _, after, ok := strings.Cut(section, "### Environment overrides\n"); ok {
+		section = after
+	}
+	section, _, _ = strings.Cut(section, "\n#")
+
+	var rows []string
+	for _, line := range strings.Split(section, "\n") {
+		if strings.HasPrefix(line, "| `GOREG_") {
+			cells := strings.Split(line, "|")
+			rows = append(rows, strings.TrimSpace(cells[1])+" "+strings.TrimSpace(cells[2]))
+		}
+	}
+
+	var expected []string
+	for _, b := range envBindings(&model.Config{}) {
+		key := "`" + b.Key[len(b.Key)-1] + "`"
+		if len(b.Key) > 1 {
+			key = "`[" + strings.Join(b.Key[:len(b.Key)-1], ".") + "] " + b.Key[len(b.Key)-1] + "`"
+		}
+		expected = append(expected, "`"+b.Name+"` "+key)
+	}
+
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("README environment table is out of date\nexpected %q\ngot      %q", expected, rows)
+	}
+}
@@ -0,0 +1,133 @@
+package common
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type gitIgnoreRule struct {
+	pattern string
+	negate  bool
+	dirOnly bool
+}
+
+// GitIgnore answers whether paths inside a git work tree are ignored by the
+// .gitignore files of their directories and .git/info/exclude. It supports
+// comments, negation, directory-only patterns, anchored patterns and "**".
+type GitIgnore struct {
+	root  string
+	rules map[string][]gitIgnoreRule
+}
+
+// NewGitIgnore returns the matcher for the work tree containing dir, or nil
+// when dir is not inside a git work tree.
+func NewGitIgnore(dir string) (*GitIgnore, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	root := absDir
+	for {
+		if _, err := os.Stat(filepath.Join(root, ".git")); err == nil {
+			break
+		}
+		parent := filepath.Dir(root)
+		if parent == root {
+			return nil, nil
+		}
+		root = parent
+	}
+
+	g := &GitIgnore{root: root, rules: make(map[string][]gitIgnoreRule)}
+	g.rules[root] = append(readGitIgnoreRules(filepath.Join(root, ".git", "info", "exclude")),
+		readGitIgnoreRules(filepath.Join(root, ".gitignore"))...)
+	return g, nil
+}
+
+// Ignored reports whether path is ignored. Callers walking a tree should
+// skip ignored directories, since their contents are ignored as well.
+func (g *GitIgnore) Ignored(path string, isDir bool) bool {
+	if g == nil {
+		return false
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(g.root, absPath)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
+	}
+
+	dirs := []string{g.root}
+	if parent := filepath.Dir(rel); parent != "." {
+		dir := g.root
+		for _, segment := range strings.Split(parent, string(filepath.Separator)) {
+			dir = filepath.Join(dir, segment)
+			dirs = append(dirs, dir)
+		}
+	}
+
+	ignored := false
+	for _, dir := range dirs {
+		name, _ := filepath.Rel(dir, absPath)
+		for _, rule := range g.rulesFor(dir) {
+			if rule.dirOnly && !isDir {
+				continue
+			}
+			if MatchGlob(rule.pattern, filepath.ToSlash(name)) {
+				ignored = !rule.negate
+			}
+		}
+	}
+	return ignored
+}
+
+func (g *GitIgnore) rulesFor(dir string) []gitIgnoreRule {
+	if rules, ok := g.rules[dir]; ok {
+		return rules
+	}
+	rules := readGitIgnoreRules(filepath.Join(dir, ".gitignore"))
+	g.rules[dir] = rules
+	return rules
+}
+
+func readGitIgnoreRules(path string) []gitIgnoreRule {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var rules []gitIgnoreRule
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rule gitIgnoreRule
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		rule.pattern = line
+		rules = append(rules, rule)
+	}
+	return rules
+}
@@ -0,0 +1,81 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGitIgnore(t *testing.T) {
+	root := t.TempDir()
+
+	files := map[string]string{
+		".git/info/exclude":    "local_only.go\n",
+		".gitignore":           "# build output\nbuild/\n*.gen.go\n!keep.gen.go\n/rootonly.go\n",
+		"sub/.gitignore":       "secret.go\n",
+		"sub/nested/.keep":     "",
+		"build/main.go":        "",
+		"api/model.gen.go":     "",
+		"api/keep.gen.go":      "",
+		"rootonly.go":          "",
+		"sub/rootonly.go":      "",
+		"sub/secret.go":        "",
+		"sub/nested/secret.go": "",
+		"secret.go":            "",
+		"local_only.go":        "",
+		"main.go":              "",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	g, err := NewGitIgnore(filepath.Join(root, "sub"))
+	if err != nil {
+		t.Fatalf("NewGitIgnore failed: %v", err)
+	}
+	if g == nil {
+		t.Fatal("expected a matcher inside a git work tree, got nil")
+	}
+
+	tests := []struct {
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{"build", true, true},
+		{"api/model.gen.go", false, true},
+		{"api/keep.gen.go", false, false},
+		{"rootonly.go", false, true},
+		{"sub/rootonly.go", false, false},
+		{"sub/secret.go", false, true},
+		{"sub/nested/secret.go", false, true},
+		{"secret.go", false, false},
+		{"local_only.go", false, true},
+		{"main.go", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := g.Ignored(filepath.Join(root, filepath.FromSlash(tt.path)), tt.isDir); got != tt.expected {
+			t.Errorf("Ignored(%q) = %v, want %v", tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestNewGitIgnore_OutsideWorkTree(t *testing.T) {
+	g, err := NewGitIgnore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewGitIgnore failed: %v", err)
+	}
+	if g != nil {
+		t.Errorf("expected nil matcher outside a git work tree, got %+v", g)
+	}
+	if g.Ignored("main.go", false) {
+		t.Error("expected nil matcher to ignore nothing")
+	}
+}
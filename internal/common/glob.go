@@ -0,0 +1,46 @@
+package common
+
+import (
+	"path"
+	"strings"
+)
+
+// MatchGlob reports whether the slash-separated name matches pattern.
+// Each pattern segment is matched with path.Match, and a "**" segment
+// matches zero or more whole segments. A pattern without a slash matches
+// the base name of name at any depth, like in .gitignore.
+func MatchGlob(pattern, name string) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+	name = strings.TrimPrefix(name, "./")
+
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(name))
+		return ok
+	}
+
+	return matchSegments(strings.Split(strings.TrimPrefix(pattern, "/"), "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(rest, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
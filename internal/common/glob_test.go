@@ -0,0 +1,32 @@
+package common
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{"*.pb.go", "api/v1/service.pb.go", true},
+		{"*.pb.go", "service.go", false},
+		{"**/*_test.go", "main_test.go", true},
+		{"**/*_test.go", "internal/core/importer_test.go", true},
+		{"**/*_test.go", "internal/core/importer.go", false},
+		{"internal/gen/**", "internal/gen/model.go", true},
+		{"internal/gen/**", "internal/gen/sub/model.go", true},
+		{"internal/gen/**", "internal/general/model.go", false},
+		{"vendor/**", "vendor/github.com/pkg/errors/errors.go", true},
+		{"/cmd/*.go", "cmd/main.go", true},
+		{"cmd/*.go", "internal/cmd/main.go", false},
+		{"./cmd/*.go", "cmd/main.go", true},
+		{"internal/**/mock_*.go", "internal/a/b/mock_client.go", true},
+		{"internal/**/mock_*.go", "internal/mock_client.go", true},
+	}
+
+	for _, tt := range tests {
+		if got := MatchGlob(tt.pattern, tt.name); got != tt.expected {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.expected)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"bytes"
 	"errors"
 	"os"
 	"path/filepath"
@@ -34,11 +35,11 @@ func FindGoregToml() (string, error) {
 		dir = parentDir
 	}
 
-	homeDir, err := os.UserHomeDir()
+	configDir, err := GlobalConfigDir()
 	if err != nil {
 		return "", err
 	}
-	configPath := filepath.Join(homeDir, ".config", "goreg", "goreg.toml")
+	configPath := filepath.Join(configDir, "goreg.toml")
 	if _, err := os.Stat(configPath); err == nil {
 		return configPath, nil
 	}
@@ -46,6 +47,25 @@ func FindGoregToml() (string, error) {
 	return "", errors.New("goreg.toml not found")
 }
 
+// GlobalConfigDir returns the directory holding the user-wide goreg.toml:
+// $XDG_CONFIG_HOME/goreg when XDG_CONFIG_HOME is an absolute path,
+// ~/.config/goreg otherwise.
+func GlobalConfigDir() (string, error) {
+	if xdgConfigHome := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(xdgConfigHome) {
+		return filepath.Join(xdgConfigHome, "goreg"), nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "goreg"), nil
+}
+
+// LoadToml strictly decodes the goreg.toml at filePath. Unknown keys, type
+// mismatches and invalid values are reported as *ConfigError values that
+// carry the offending line and column; unknown keys and invalid values are
+// reported together.
 func LoadToml(filePath string) (*model.Config, error) {
 
 	data, err := os.ReadFile(filePath)
@@ -53,20 +73,65 @@ func LoadToml(filePath string) (*model.Config, error) {
 		return nil, err
 	}
 
-	var cfg *model.Config
-	if err := toml.Unmarshal(data, &cfg); err != nil {
+	cfg := &model.Config{}
+	decoder := toml.NewDecoder(bytes.NewReader(data)).DisallowUnknownFields()
+	decodeErr := decoder.Decode(cfg)
+	var strictErr *toml.StrictMissingError
+	if decodeErr != nil && !errors.As(decodeErr, &strictErr) {
+		return nil, wrapDecodeError(filePath, decodeErr)
+	}
+
+	// Unknown keys do not stop the decoder, so the keys it did decode are
+	// validated as well and every problem is reported at once.
+	var errs []error
+	if decodeErr != nil {
+		errs = append(errs, splitErrors(wrapDecodeError(filePath, decodeErr))...)
+	}
+	if err := cfg.Validate(); err != nil {
+		errs = append(errs, splitErrors(wrapValidateError(filePath, data, err))...)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	cfg.SetDefaults()
+	cfg.FilePath = filePath
+	return cfg, nil
+}
+
+// LoadConfig loads configPath when it is given, otherwise the goreg.toml
+// found by FindGoregToml. With noConfig, or when nothing is found, the
+// defaults are used. GOREG_* environment variables are applied on top.
+func LoadConfig(configPath string, noConfig bool) (*model.Config, error) {
+	cfg, err := loadConfigFile(configPath, noConfig)
+	if err != nil {
 		return nil, err
 	}
 
+	if err := ApplyEnv(cfg); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
 
-func LoadConfig() (*model.Config, error) {
+func loadConfigFile(configPath string, noConfig bool) (*model.Config, error) {
+	if noConfig {
+		return defaultConfig(), nil
+	}
+
+	if configPath != "" {
+		return LoadToml(configPath)
+	}
+
 	if filePath, err := FindGoregToml(); err != nil {
-		cfg := &model.Config{}
-		cfg.SetDefaults()
-		return cfg, nil
+		return defaultConfig(), nil
 	} else {
 		return LoadToml(filePath)
 	}
 }
+
+func defaultConfig() *model.Config {
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+	return cfg
+}
@@ -1,8 +1,11 @@
 package common
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -128,8 +131,200 @@ invalid_field == "error"  # ← TOML syntax error
 	}
 }
 
+func TestLoadToml_Strict(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		line     int
+		column   int
+		contains string
+	}{
+		{
+			name: "Unknown key",
+			content: `[import]
+organisation_module = "github.com/example_org"
+`,
+			line:     2,
+			column:   1,
+			contains: `unknown key "import.organisation_module"`,
+		},
+		{
+			name: "Invalid order value",
+			content: `[import]
+local_module = "example_project"
+  order = "std,thirdparty,org"
+`,
+			line:     3,
+			column:   3,
+			contains: "import.order",
+		},
+		{
+			name: "Invalid order in second override",
+			content: `[[overrides]]
+files = ["*_test.go"]
+
+[[overrides]]
+files = ["internal/gen/**"]
+[overrides.import]
+order = "std,local"
+`,
+			line:     7,
+			column:   1,
+			contains: "overrides.1.import.order",
+		},
+		{
+			name: "Override without files",
+			content: `[[overrides]]
+[overrides.format]
+minimize_group = true
+`,
+			contains: "overrides.0.files",
+		},
+		{
+			name: "Invalid alias placement",
+			content: `[format.alias_placement]
+blank = "own-group"
+alias = "first"
+`,
+			line:     3,
+			column:   1,
+			contains: "format.alias_placement.alias",
+		},
+		{
+			name: "Invalid pinned entry",
+			content: `[format]
+pinned = ["context", "x \"a\" b"]
+`,
+			line:     2,
+			column:   1,
+			contains: "format.pinned",
+		},
+		{
+			name: "Layer without deny",
+			content: `[[rules.layers]]
+from = "<local>/internal/..."
+deny = []
+`,
+			line:     3,
+			column:   1,
+			contains: "rules.layers.0.deny",
+		},
+		{
+			name: "Invalid alias",
+			content: `[aliases]
+"k8s.io/api/core/v1" = "core-v1"
+`,
+			line:     2,
+			column:   1,
+			contains: "aliases.k8s.io/api/core/v1",
+		},
+		{
+			name: "Multi-line group header",
+			content: `[format.group_headers]
+std = """
+Standard
+library"""
+`,
+			line:     2,
+			column:   1,
+			contains: "format.group_headers.std",
+		},
+		{
+			name: "Type mismatch",
+			content: `[format]
+minimize_group = 1
+`,
+			line:     2,
+			contains: "bool",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempFile := filepath.Join(t.TempDir(), "goreg.toml")
+			if err := os.WriteFile(tempFile, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to create temp goreg.toml: %v", err)
+			}
+
+			_, err := LoadToml(tempFile)
+			if err == nil {
+				t.Fatal("expected error, but got nil")
+			}
+
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) {
+				t.Fatalf("expected *ConfigError, got %T: %v", err, err)
+			}
+			if configErr.Path != tempFile {
+				t.Errorf("expected path %s, got %s", tempFile, configErr.Path)
+			}
+			if configErr.Line != tt.line {
+				t.Errorf("expected line %d, got %d", tt.line, configErr.Line)
+			}
+			if tt.column != 0 && configErr.Column != tt.column {
+				t.Errorf("expected column %d, got %d", tt.column, configErr.Column)
+			}
+			if !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("expected error to contain %q, got %q", tt.contains, err.Error())
+			}
+		})
+	}
+}
+
+func TestLoadToml_StrictReportsEveryError(t *testing.T) {
+	tempFile := filepath.Join(t.TempDir(), "goreg.toml")
+	content := `[import]
+organisation_module = "github.com/example_org"
+order = "std,thirdparty,org"
+`
+	if err := os.WriteFile(tempFile, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to create temp goreg.toml: %v", err)
+	}
+
+	_, err := LoadToml(tempFile)
+	if err == nil {
+		t.Fatal("expected error, but got nil")
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("expected joined errors, got %T: %v", err, err)
+	}
+	var lines []int
+	for _, e := range joined.Unwrap() {
+		var configErr *ConfigError
+		if !errors.As(e, &configErr) {
+			t.Fatalf("expected *ConfigError, got %T: %v", e, e)
+		}
+		lines = append(lines, configErr.Line)
+	}
+	if !reflect.DeepEqual(lines, []int{2, 3}) {
+		t.Errorf("expected errors on lines [2 3], got %v: %v", lines, err)
+	}
+	for _, contains := range []string{`unknown key "import.organisation_module"`, "import.order"} {
+		if !strings.Contains(err.Error(), contains) {
+			t.Errorf("expected error to contain %q, got %q", contains, err.Error())
+		}
+	}
+}
+
+func TestLoadToml_Empty(t *testing.T) {
+	tempFile := filepath.Join(t.TempDir(), "goreg.toml")
+	if err := os.WriteFile(tempFile, []byte(""), 0644); err != nil {
+		t.Fatalf("failed to create temp goreg.toml: %v", err)
+	}
+
+	cfg, err := LoadToml(tempFile)
+	if err != nil {
+		t.Fatalf("failed to load empty toml: %v", err)
+	}
+	if cfg.Import.Order != "std,thirdparty,organization,local" {
+		t.Errorf("expected default import order, got %s", cfg.Import.Order)
+	}
+}
+
 func TestLoadConfig(t *testing.T) {
-	cfg, err := LoadConfig()
+	cfg, err := LoadConfig("", false)
 	if err != nil {
 		t.Fatalf("LoadConfig failed: %v", err)
 	}
@@ -138,3 +333,84 @@ func TestLoadConfig(t *testing.T) {
 		t.Errorf("expected default import order to be 'std,thirdparty,organization,local', got %s", cfg.Import.Order)
 	}
 }
+
+func TestLoadConfig_ExplicitPath(t *testing.T) {
+	tempFile := filepath.Join(t.TempDir(), "custom.toml")
+	if err := os.WriteFile(tempFile, []byte("[import]\norganization_module = \"github.com/explicit\"\n"), 0644); err != nil {
+		t.Fatalf("failed to create temp toml: %v", err)
+	}
+
+	cfg, err := LoadConfig(tempFile, false)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Import.OrganizationModule != "github.com/explicit" {
+		t.Errorf("expected organization_module from explicit config, got %s", cfg.Import.OrganizationModule)
+	}
+	if cfg.FilePath != tempFile {
+		t.Errorf("expected FilePath %s, got %s", tempFile, cfg.FilePath)
+	}
+
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.toml"), false); err == nil {
+		t.Error("expected error for missing explicit config, but got nil")
+	}
+}
+
+func TestLoadConfig_NoConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "goreg.toml"), []byte("[import]\norganization_module = \"github.com/found\"\n"), 0644); err != nil {
+		t.Fatalf("failed to create temp goreg.toml: %v", err)
+	}
+
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current working directory: %v", err)
+	}
+	defer os.Chdir(originalWd)
+
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("failed to change working directory: %v", err)
+	}
+
+	cfg, err := LoadConfig("", true)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Import.OrganizationModule != "" || cfg.FilePath != "" {
+		t.Errorf("expected defaults with noConfig, got %+v", cfg)
+	}
+}
+
+func TestFindGoregToml_XDGConfigHome(t *testing.T) {
+	workDir := t.TempDir()
+	xdgDir := t.TempDir()
+
+	configPath := filepath.Join(xdgDir, "goreg", "goreg.toml")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte(""), 0644); err != nil {
+		t.Fatalf("failed to create goreg.toml: %v", err)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", xdgDir)
+	t.Setenv("HOME", t.TempDir())
+
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current working directory: %v", err)
+	}
+	defer os.Chdir(originalWd)
+
+	if err := os.Chdir(workDir); err != nil {
+		t.Fatalf("failed to change working directory: %v", err)
+	}
+
+	foundPath, err := FindGoregToml()
+	if err != nil {
+		t.Fatalf("expected to find goreg.toml under XDG_CONFIG_HOME, but got error: %v", err)
+	}
+	if foundPath != configPath {
+		t.Errorf("expected %s, got %s", configPath, foundPath)
+	}
+}
@@ -0,0 +1,57 @@
+package configcmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/magicdrive/goreg/internal/common"
+)
+
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+func Execute(args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("a config subcommand is required: validate")
+	}
+
+	switch args[0] {
+	case "validate":
+		return Validate(args[1:], w)
+	default:
+		return fmt.Errorf("unknown config subcommand: %s", args[0])
+	}
+}
+
+// Validate strictly loads the goreg.toml given in args, or the one goreg
+// would discover, and writes every problem found to w.
+func Validate(args []string, w io.Writer) error {
+	var filePath string
+	if len(args) > 0 {
+		filePath = args[0]
+	} else {
+		found, err := common.FindGoregToml()
+		if err != nil {
+			return err
+		}
+		filePath = found
+	}
+
+	if _, err := common.LoadToml(filePath); err != nil {
+		var configErr *common.ConfigError
+		if !errors.As(err, &configErr) {
+			return err
+		}
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range joined.Unwrap() {
+				fmt.Fprintln(w, e)
+			}
+		} else {
+			fmt.Fprintln(w, err)
+		}
+		return ErrInvalidConfig
+	}
+
+	fmt.Fprintf(w, "%s: OK\n", filePath)
+	return nil
+}
@@ -0,0 +1,115 @@
+package configcmd_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/magicdrive/goreg/internal/configcmd"
+)
+
+func writeToml(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "goreg.toml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write goreg.toml: %v", err)
+	}
+	return path
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantErr  bool
+		expected []string
+	}{
+		{
+			name: "Valid config",
+			content: `
+[import]
+order = "std,thirdparty,organization,local"
+
+[format]
+minimize_group = true
+`,
+			wantErr:  false,
+			expected: []string{": OK"},
+		},
+		{
+			name: "Unknown key",
+			content: `
+[import]
+organisation_module = "github.com/myorg"
+`,
+			wantErr:  true,
+			expected: []string{":3:1: unknown key \"import.organisation_module\""},
+		},
+		{
+			name: "Invalid order",
+			content: `
+[import]
+order = "std,local"
+`,
+			wantErr:  true,
+			expected: []string{":3:1: import.order: order must include all of"},
+		},
+		{
+			name: "Type error",
+			content: `
+[format]
+minimize_group = "yes"
+`,
+			wantErr:  true,
+			expected: []string{":3:"},
+		},
+		{
+			name: "Unknown key in format table",
+			content: `
+[import]
+order = "std,foo"
+
+[format]
+sort_aliases = true
+`,
+			wantErr: true,
+			expected: []string{
+				":6:1: unknown key \"format.sort_aliases\"",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeToml(t, tt.content)
+
+			var buf bytes.Buffer
+			err := configcmd.Execute([]string{"validate", path}, &buf)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error status: %v\n%s", err, buf.String())
+			}
+			if err != nil && !errors.Is(err, configcmd.ErrInvalidConfig) {
+				t.Errorf("expected ErrInvalidConfig, got %v", err)
+			}
+
+			for _, want := range tt.expected {
+				if !strings.Contains(buf.String(), path+want) {
+					t.Errorf("expected output to contain %q, got:\n%s", path+want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestExecute_UnknownSubcommand(t *testing.T) {
+	var buf bytes.Buffer
+	if err := configcmd.Execute([]string{"show"}, &buf); err == nil {
+		t.Fatal("expected error for unknown subcommand, but got nil")
+	}
+	if err := configcmd.Execute(nil, &buf); err == nil {
+		t.Fatal("expected error for missing subcommand, but got nil")
+	}
+}
@@ -0,0 +1,256 @@
+package core
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/magicdrive/goreg/internal/commandline"
+)
+
+// textEdit replaces src[start:end] with text.
+type textEdit struct {
+	start int
+	end   int
+	text  string
+}
+
+// RewriteAliases applies the [aliases] table and remove_redundant_alias to
+// src: import specs get their configured alias, aliases equal to the package
+// name are dropped, and the uses of a renamed import are renamed with it.
+// The package name of an unaliased import is told from its path and the
+// modules in opt, and imports whose new name is already taken in the file are
+// left alone, as are files that do not parse. With opt.VerifyFlag, the
+// result is checked by VerifyAliases.
+func RewriteAliases(src []byte, opt *commandline.Option) ([]byte, error) {
+	if len(opt.Aliases) == 0 && !opt.RemoveRedundantAliasFlag {
+		return src, nil
+	}
+
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return src, nil
+	}
+	file := fset.File(node.Package)
+
+	taken := usedNames(node)
+	renames := make(map[string]string)
+	var edits []textEdit
+	for _, imp := range node.Imports {
+		path := importPath(imp)
+		if path == cgoPath {
+			continue
+		}
+
+		current, want, ok := aliasRename(imp, opt)
+		if !ok {
+			continue
+		}
+
+		if want != current {
+			if taken[want] {
+				continue
+			}
+			taken[want] = true
+			renames[current] = want
+			edits = append(edits, renameUses(node, file, current, want)...)
+		}
+
+		// An alias equal to the package name is only kept when it is
+		// already there and remove_redundant_alias is off.
+		implicit := knownPackageName(path, opt)
+		alias := want
+		if want != "" && want == implicit && (imp.Name == nil || want != current || opt.RemoveRedundantAliasFlag) {
+			alias = ""
+		}
+
+		switch {
+		case imp.Name == nil && alias != "":
+			edits = append(edits, textEdit{file.Offset(imp.Path.Pos()), file.Offset(imp.Path.Pos()), alias + " "})
+		case imp.Name != nil && alias == "":
+			edits = append(edits, textEdit{file.Offset(imp.Name.Pos()), file.Offset(imp.Path.Pos()), ""})
+		case imp.Name != nil && alias != imp.Name.Name:
+			edits = append(edits, textEdit{file.Offset(imp.Name.Pos()), file.Offset(imp.Name.End()), alias})
+		}
+	}
+
+	result := applyEdits(src, edits)
+	if opt.VerifyFlag {
+		if err := VerifyAliases(src, result, renames); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// aliasRename returns the name imp is used by in the file and the name it
+// should be used by. ok is false for blank and dot imports and for unaliased
+// imports whose package name cannot be told from the path.
+func aliasRename(imp *ast.ImportSpec, opt *commandline.Option) (string, string, bool) {
+	path := importPath(imp)
+
+	current := knownPackageName(path, opt)
+	if imp.Name != nil {
+		current = imp.Name.Name
+	}
+	if current == "" || current == "_" || current == "." {
+		return "", "", false
+	}
+
+	want := current
+	if alias, ok := opt.Aliases[path]; ok && token.IsIdentifier(alias) && alias != "_" {
+		want = alias
+	}
+	return current, want, true
+}
+
+// knownPackageName returns the package name of an unaliased import of path
+// when the path tells it: its last element, if that is an identifier. A
+// major version such as v1 names the package only when it is a directory
+// below the module containing path (k8s.io/api/core/v1 in k8s.io/api), not
+// the version of the module itself (github.com/google/go-github/v50); the
+// module is looked up among opt.ModulePath and the modules required in
+// go.mod. Otherwise it returns "".
+func knownPackageName(path string, opt *commandline.Option) string {
+	name := lastElement(path)
+	if !token.IsIdentifier(name) {
+		return ""
+	}
+	if isMajorVersion(name) {
+		module := containingModule(path, opt)
+		if module == "" || module == path {
+			return ""
+		}
+	}
+	return name
+}
+
+// containingModule returns the longest of opt.ModulePath and opt.Requires
+// that contains path, or "" when there is none.
+func containingModule(path string, opt *commandline.Option) string {
+	var module string
+	for _, candidate := range append([]string{opt.ModulePath}, opt.Requires...) {
+		if candidate == "" {
+			continue
+		}
+		if (path == candidate || strings.HasPrefix(path, candidate+"/")) && len(candidate) > len(module) {
+			module = candidate
+		}
+	}
+	return module
+}
+
+func lastElement(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// usedNames returns the local names of the imports of node and every
+// identifier its declarations refer to, leaving out selected fields and
+// methods.
+func usedNames(node *ast.File) map[string]bool {
+	names := make(map[string]bool)
+	for _, imp := range node.Imports {
+		if imp.Name != nil {
+			names[imp.Name.Name] = true
+		} else {
+			names[assumedPackageName(importPath(imp))] = true
+			names[lastElement(importPath(imp))] = true
+		}
+	}
+
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.ImportSpec:
+			return false
+		case *ast.SelectorExpr:
+			ast.Inspect(x.X, visit)
+			return false
+		case *ast.Ident:
+			names[x.Name] = true
+		}
+		return true
+	}
+	for _, decl := range node.Decls {
+		ast.Inspect(decl, visit)
+	}
+	return names
+}
+
+// renameUses returns the edits renaming the package name from to to in
+// qualified identifiers such as from.Println. Identifiers resolved to a
+// local declaration shadowing the import are not package uses.
+func renameUses(node *ast.File, file *token.File, from, to string) []textEdit {
+	var edits []textEdit
+	ast.Inspect(node, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); ok && x.Name == from && x.Obj == nil {
+			edits = append(edits, textEdit{file.Offset(x.Pos()), file.Offset(x.End()), to})
+		}
+		return true
+	})
+	return edits
+}
+
+func applyEdits(src []byte, edits []textEdit) []byte {
+	if len(edits) == 0 {
+		return src
+	}
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].start < edits[j].start
+	})
+
+	result := make([]byte, 0, len(src))
+	last := 0
+	for _, e := range edits {
+		result = append(result, src[last:e.start]...)
+		result = append(result, e.text...)
+		last = e.end
+	}
+	return append(result, src[last:]...)
+}
+
+// AliasUse is an import spec of a file: its path, its alias, empty when it
+// has none, and the name the package is used by in the file, empty when it
+// cannot be told from the path.
+type AliasUse struct {
+	Path  string
+	Alias string
+	Name  string
+}
+
+// AliasUses returns the import specs of src in source order, leaving out
+// blank, dot and cgo imports. opt tells the modules package names are looked
+// up in.
+func AliasUses(src []byte, opt *commandline.Option) ([]AliasUse, error) {
+	fset := token.NewFileSet()
+	node, err := parseImports(fset, src)
+	if err != nil {
+		return nil, err
+	}
+
+	var uses []AliasUse
+	for _, imp := range node.Imports {
+		path := importPath(imp)
+		if path == cgoPath {
+			continue
+		}
+
+		use := AliasUse{Path: path, Name: knownPackageName(path, opt)}
+		if imp.Name != nil {
+			if imp.Name.Name == "_" || imp.Name.Name == "." {
+				continue
+			}
+			use.Alias = imp.Name.Name
+			use.Name = imp.Name.Name
+		}
+		uses = append(uses, use)
+	}
+	return uses, nil
+}
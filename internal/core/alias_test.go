@@ -0,0 +1,211 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/magicdrive/goreg/internal/commandline"
+	"github.com/magicdrive/goreg/internal/core"
+)
+
+func TestRewriteAliases(t *testing.T) {
+	cases := []struct {
+		name      string
+		aliases   map[string]string
+		redundant bool
+		requires  []string
+		input     string
+		expected  string
+	}{
+		{
+			name:    "Add and rename aliases",
+			aliases: map[string]string{"github.com/sirupsen/logrus": "log", "k8s.io/api/core/v1": "corev1"},
+			input: `package main
+
+import (
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+)
+
+var pod v1.Pod
+
+func main() {
+	logrus.Info(pod.Name)
+}
+`,
+			expected: `package main
+
+import (
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var pod corev1.Pod
+
+func main() {
+	log.Info(pod.Name)
+}
+`,
+		},
+		{
+			name:      "Remove redundant aliases",
+			redundant: true,
+			input: `package main
+
+import (
+	fmt "fmt"
+	errors "github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v3"
+)
+`,
+			expected: `package main
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v3"
+)
+`,
+		},
+		{
+			name:    "Configured alias equal to the package name",
+			aliases: map[string]string{"github.com/pkg/errors": "errors"},
+			input: `package main
+
+import pkgerrors "github.com/pkg/errors"
+
+var err = pkgerrors.New("x")
+`,
+			expected: `package main
+
+import "github.com/pkg/errors"
+
+var err = errors.New("x")
+`,
+		},
+		{
+			name:    "Unaliased version outside known modules is left alone",
+			aliases: map[string]string{"k8s.io/api/core/v1": "corev1"},
+			input: `package main
+
+import "k8s.io/api/core/v1"
+
+var pod v1.Pod
+`,
+			expected: `package main
+
+import "k8s.io/api/core/v1"
+
+var pod v1.Pod
+`,
+		},
+		{
+			name:     "Unaliased package directory named like a version",
+			aliases:  map[string]string{"k8s.io/api/core/v1": "corev1"},
+			requires: []string{"k8s.io/api"},
+			input: `package main
+
+import "k8s.io/api/core/v1"
+
+var pod v1.Pod
+`,
+			expected: `package main
+
+import corev1 "k8s.io/api/core/v1"
+
+var pod corev1.Pod
+`,
+		},
+		{
+			name:     "Unaliased module version is left alone",
+			aliases:  map[string]string{"github.com/google/go-github/v50": "gh"},
+			requires: []string{"github.com/google/go-github/v50"},
+			input: `package main
+
+import "github.com/google/go-github/v50"
+
+var client github.Client
+`,
+			expected: `package main
+
+import "github.com/google/go-github/v50"
+
+var client github.Client
+`,
+		},
+		{
+			name:    "Name already taken",
+			aliases: map[string]string{"github.com/sirupsen/logrus": "log"},
+			input: `package main
+
+import (
+	"log"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	log.Print(logrus.InfoLevel)
+}
+`,
+			expected: `package main
+
+import (
+	"log"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	log.Print(logrus.InfoLevel)
+}
+`,
+		},
+		{
+			name:    "Shadowed package name is not renamed",
+			aliases: map[string]string{"github.com/sirupsen/logrus": "lr"},
+			input: `package main
+
+import "github.com/sirupsen/logrus"
+
+func main() {
+	logrus.Info("a")
+	{
+		logrus := struct{ Info func(string) }{}
+		logrus.Info("b")
+	}
+}
+`,
+			expected: `package main
+
+import lr "github.com/sirupsen/logrus"
+
+func main() {
+	lr.Info("a")
+	{
+		logrus := struct{ Info func(string) }{}
+		logrus.Info("b")
+	}
+}
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			opt := &commandline.Option{
+				Aliases:                  tc.aliases,
+				RemoveRedundantAliasFlag: tc.redundant,
+				Requires:                 tc.requires,
+				VerifyFlag:               true,
+			}
+
+			output, err := core.RewriteAliases([]byte(tc.input), opt)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(output) != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, string(output))
+			}
+		})
+	}
+}
@@ -1,10 +1,16 @@
 package core_test
 
 import (
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 
+	"golang.org/x/tools/imports"
+
 	"github.com/magicdrive/goreg/internal/commandline"
 	"github.com/magicdrive/goreg/internal/core"
+	"github.com/magicdrive/goreg/internal/model"
 )
 
 var sampleGoCode = []byte(`package main
@@ -21,6 +27,14 @@ func main() {
 }
 `)
 
+// largeGoFiles are real-world files from GOROOT used to measure goreg on
+// sizes found in practice. Missing files are skipped.
+var largeGoFiles = []string{
+	"src/net/http/server.go",
+	"src/go/types/expr.go",
+	"src/runtime/proc.go",
+}
+
 func BenchmarkFormatImports(b *testing.B) {
 	opt := &commandline.Option{
 		ImportOrder:          nil,
@@ -42,3 +56,43 @@ func BenchmarkFormatImports(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkFormatImports_LargeFiles(b *testing.B) {
+	opt := &commandline.Option{
+		ImportOrder: model.DefaultOrder,
+		ModulePath:  "github.com/test/project",
+	}
+
+	for _, name := range largeGoFiles {
+		src, err := os.ReadFile(filepath.Join(runtime.GOROOT(), name))
+		if err != nil {
+			b.Logf("skipping %s: %v", name, err)
+			continue
+		}
+
+		b.Run(filepath.Base(name)+"/fast", func(b *testing.B) {
+			b.SetBytes(int64(len(src)))
+			for i := 0; i < b.N; i++ {
+				if _, err := core.FormatImports(src, opt); err != nil {
+					b.Fatalf("FormatImports failed: %v", err)
+				}
+			}
+		})
+
+		b.Run(filepath.Base(name)+"/gofmt", func(b *testing.B) {
+			b.SetBytes(int64(len(src)))
+			for i := 0; i < b.N; i++ {
+				formatted, err := imports.Process(name, src, &imports.Options{
+					FormatOnly: true,
+					Comments:   true,
+				})
+				if err != nil {
+					b.Fatalf("imports.Process failed: %v", err)
+				}
+				if _, err := core.FormatImports(formatted, opt); err != nil {
+					b.Fatalf("FormatImports failed: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// cgoPath is the import path of cgo's pseudo-package. cgo reads the comment
+// right before `import "C"` as C source, so goreg never moves it.
+const cgoPath = "C"
+
+func isCgoImport(imp *ast.ImportSpec) bool {
+	return importPath(imp) == cgoPath
+}
+
+// isCgoDecl reports whether decl imports nothing but "C". Such declarations
+// are left exactly where they are.
+func isCgoDecl(decl *ast.GenDecl) bool {
+	for _, spec := range decl.Specs {
+		if !isCgoImport(spec.(*ast.ImportSpec)) {
+			return false
+		}
+	}
+	return len(decl.Specs) > 0
+}
+
+// cgoImportsText pulls every "C" spec out of the import declarations goreg
+// rewrites, turning each into its own `import "C"` declaration directly
+// below its preamble. The result goes in front of the rewritten block.
+func cgoImportsText(src []byte, fset *token.FileSet, node *ast.File) string {
+	var builder strings.Builder
+
+	for _, decl := range importDecls(node) {
+		for _, spec := range decl.Specs {
+			imp := spec.(*ast.ImportSpec)
+			if !isCgoImport(imp) {
+				continue
+			}
+
+			if imp.Doc != nil {
+				for _, c := range imp.Doc.List {
+					builder.WriteString(c.Text + "\n")
+				}
+			}
+
+			file := fset.File(imp.Pos())
+			builder.WriteString("import " + string(src[file.Offset(imp.Pos()):file.Offset(imp.End())]))
+			if imp.Comment != nil {
+				for _, c := range imp.Comment.List {
+					builder.WriteString(" " + c.Text)
+				}
+			}
+			builder.WriteString("\n\n")
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,160 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/magicdrive/goreg/internal/commandline"
+	"github.com/magicdrive/goreg/internal/core"
+	"github.com/magicdrive/goreg/internal/model"
+)
+
+func TestFormatImports_Cgo(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "Preamble declaration before the import block",
+			input: `package main
+
+// #include <stdio.h>
+import "C"
+
+import (
+	"os"
+	"fmt"
+)
+`,
+			expected: `package main
+
+// #include <stdio.h>
+import "C"
+
+import (
+	"fmt"
+	"os"
+)
+`,
+		},
+		{
+			name: "Cgo declaration between import declarations",
+			input: `package main
+
+import (
+	"os"
+	"fmt"
+)
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import "strings"
+`,
+			expected: `package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+`,
+		},
+		{
+			name: "Cgo spec inside an import block",
+			input: `package main
+
+import (
+	"os"
+	// #include <stdio.h>
+	"C"
+	"fmt"
+)
+`,
+			expected: `package main
+
+// #include <stdio.h>
+import "C"
+
+import (
+	"fmt"
+	"os"
+)
+`,
+		},
+		{
+			name: "Only a cgo declaration",
+			input: `package main
+
+// #include <stdio.h>
+import ("C")
+`,
+			expected: `package main
+
+// #include <stdio.h>
+import ("C")
+`,
+		},
+	}
+
+	opt := &commandline.Option{
+		ImportOrder: model.DefaultOrder,
+		ModulePath:  "myproject/module",
+		VerifyFlag:  true,
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := core.FormatImports([]byte(tc.input), opt)
+			if err != nil {
+				t.Fatalf("FormatImports failed: %v", err)
+			}
+			if string(output) != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, string(output))
+			}
+		})
+	}
+}
+
+func TestExplainImports_Cgo(t *testing.T) {
+	input := `package main
+
+// #include <stdio.h>
+import "C"
+
+import (
+	"os"
+	"fmt"
+)
+`
+	opt := &commandline.Option{
+		ImportOrder: model.DefaultOrder,
+		ModulePath:  "myproject/module",
+	}
+
+	got, err := core.ExplainImports([]byte(input), opt)
+	if err != nil {
+		t.Fatalf("ExplainImports failed: %v", err)
+	}
+
+	expected := []model.ImportExplanation{
+		{Path: "C", Line: 4, Group: model.Cgo, Rule: model.RuleCgo, Position: 0},
+		{Path: "os", Line: 7, Group: model.StdLib, Rule: model.RuleStdLib, Position: 2},
+		{Path: "fmt", Line: 8, Group: model.StdLib, Rule: model.RuleStdLib, Position: 1},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d explanations, got %d", len(expected), len(got))
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("explanation %d: expected %+v, got %+v", i, expected[i], got[i])
+		}
+	}
+}
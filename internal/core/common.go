@@ -72,6 +72,64 @@ func extractModulePath(goModPath string) (string, error) {
 	return "", os.ErrNotExist
 }
 
+// GetModuleRoot returns the directory holding the go.mod of the current
+// module.
+func GetModuleRoot() (string, error) {
+	goModPath, err := findGoModFile()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Dir(goModPath), nil
+}
+
+// GetModuleRequires returns the module paths required by the go.mod of the
+// current module.
+func GetModuleRequires() ([]string, error) {
+	goModPath, err := findGoModFile()
+	if err != nil {
+		return nil, err
+	}
+
+	return extractRequires(goModPath)
+}
+
+func extractRequires(goModPath string) ([]string, error) {
+	file, err := os.Open(goModPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var requires []string
+	inBlock := false
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		line = strings.TrimSpace(line)
+
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case inBlock:
+		case line == "require (" || line == "require(":
+			inBlock = true
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "require "))
+		default:
+			continue
+		}
+
+		if fields := strings.Fields(line); len(fields) > 0 {
+			requires = append(requires, strings.Trim(fields[0], `"`))
+		}
+	}
+
+	return requires, scanner.Err()
+}
+
 var GetModulePathFromGoList = func() (string, error) {
 	cmd := exec.Command("go", "list", "-m")
 	out, err := cmd.Output()
@@ -3,6 +3,7 @@ package core_test
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/magicdrive/goreg/internal/core"
@@ -91,3 +92,33 @@ func TestGetModulePath(t *testing.T) {
 func writeGoMod(dir, content string) {
 	_ = os.WriteFile(filepath.Join(dir, "go.mod"), []byte(content), 0644)
 }
+
+func TestGetModuleRequires(t *testing.T) {
+	tempDir := t.TempDir()
+	writeGoMod(tempDir, `module github.com/test/module
+
+go 1.22
+
+require github.com/pkg/errors v0.9.1
+
+require (
+	cloud.google.com/go/storage v1.40.0
+	"k8s.io/api" v0.30.0 // indirect
+	// github.com/commented/out v1.0.0
+)
+`)
+
+	originalWD, _ := os.Getwd()
+	_ = os.Chdir(tempDir)
+	defer os.Chdir(originalWD)
+
+	requires, err := core.GetModuleRequires()
+	if err != nil {
+		t.Fatalf("GetModuleRequires failed: %v", err)
+	}
+
+	expected := []string{"github.com/pkg/errors", "cloud.google.com/go/storage", "k8s.io/api"}
+	if !reflect.DeepEqual(requires, expected) {
+		t.Errorf("expected %v, got %v", expected, requires)
+	}
+}
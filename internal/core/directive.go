@@ -0,0 +1,145 @@
+package core
+
+import (
+	"bytes"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/magicdrive/goreg/internal/commandline"
+)
+
+const (
+	ignoreDirective = "//goreg:ignore"
+	offDirective    = "//goreg:off"
+	onDirective     = "//goreg:on"
+)
+
+// frozenRegion is a //goreg:off ... //goreg:on section of an import block.
+// Its text is written back verbatim; top tells whether it preceded every
+// import goreg is free to move.
+type frozenRegion struct {
+	text  string
+	pos   token.Pos
+	end   token.Pos
+	specs []*ast.ImportSpec
+	top   bool
+}
+
+// ShouldSkip reports whether goreg must leave src untouched: it carries a
+// //goreg:ignore directive before the end of its imports, or it is a
+// generated file and generated files are not included.
+func ShouldSkip(src []byte, opt *commandline.Option) bool {
+	fset := token.NewFileSet()
+	node, err := parseImports(fset, src)
+	if err != nil {
+		return false
+	}
+
+	if !opt.IncludeGeneratedFlag && ast.IsGenerated(node) {
+		return true
+	}
+
+	for _, group := range node.Comments {
+		for _, c := range group.List {
+			if isDirective(c.Text, ignoreDirective) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isDirective(text, directive string) bool {
+	return strings.TrimSpace(text) == directive
+}
+
+func isGoregDirective(text string) bool {
+	return isDirective(text, ignoreDirective) || isDirective(text, offDirective) || isDirective(text, onDirective)
+}
+
+// findFrozenRegions returns the //goreg:off sections of the parenthesized
+// import declarations of node. A section without //goreg:on extends to the
+// closing parenthesis.
+func findFrozenRegions(src []byte, fset *token.FileSet, node *ast.File) []frozenRegion {
+	var regions []frozenRegion
+
+	for _, decl := range node.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT || !genDecl.Lparen.IsValid() {
+			continue
+		}
+
+		var offPos token.Pos = token.NoPos
+		for _, group := range node.Comments {
+			for _, c := range group.List {
+				if c.Pos() < genDecl.Lparen || c.End() > genDecl.Rparen {
+					continue
+				}
+				switch {
+				case isDirective(c.Text, offDirective) && offPos == token.NoPos:
+					offPos = c.Pos()
+				case isDirective(c.Text, onDirective) && offPos != token.NoPos:
+					regions = append(regions, newFrozenRegion(src, fset, genDecl, offPos, c.End()))
+					offPos = token.NoPos
+				}
+			}
+		}
+		if offPos != token.NoPos {
+			regions = append(regions, newFrozenRegion(src, fset, genDecl, offPos, genDecl.Rparen))
+		}
+	}
+
+	var firstMovable token.Pos = token.NoPos
+	for _, imp := range node.Imports {
+		if !inFrozenRegion(regions, imp) {
+			firstMovable = imp.Pos()
+			break
+		}
+	}
+	for i := range regions {
+		regions[i].top = firstMovable == token.NoPos || regions[i].specs == nil ||
+			regions[i].specs[0].Pos() < firstMovable
+	}
+
+	return regions
+}
+
+func newFrozenRegion(src []byte, fset *token.FileSet, decl *ast.GenDecl, from, to token.Pos) frozenRegion {
+	file := fset.File(from)
+	start := file.Offset(from)
+	end := file.Offset(to)
+
+	lineStart := bytes.LastIndexByte(src[:start], '\n') + 1
+	text := strings.TrimRight(string(src[lineStart:end]), " \t\r\n")
+
+	region := frozenRegion{text: text, pos: from, end: to}
+	for _, spec := range decl.Specs {
+		if spec.Pos() > from && spec.End() <= to {
+			region.specs = append(region.specs, spec.(*ast.ImportSpec))
+		}
+	}
+	return region
+}
+
+func inFrozenRegion(regions []frozenRegion, imp *ast.ImportSpec) bool {
+	for _, region := range regions {
+		for _, spec := range region.specs {
+			if spec == imp {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inFrozenText reports whether c lies inside one of regions, whose text is
+// written back as is.
+func inFrozenText(regions []frozenRegion, c *ast.Comment) bool {
+	for _, region := range regions {
+		if c.Pos() >= region.pos && c.End() <= region.end {
+			return true
+		}
+	}
+	return false
+}
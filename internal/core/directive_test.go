@@ -0,0 +1,173 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/magicdrive/goreg/internal/commandline"
+	"github.com/magicdrive/goreg/internal/core"
+	"github.com/magicdrive/goreg/internal/model"
+)
+
+func TestShouldSkip(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		opt      *commandline.Option
+		expected bool
+	}{
+		{
+			name: "Plain file",
+			input: `package main
+
+import "fmt"
+`,
+			opt:      &commandline.Option{},
+			expected: false,
+		},
+		{
+			name: "Generated file",
+			input: `// Code generated by protoc-gen-go. DO NOT EDIT.
+
+package main
+
+import "fmt"
+`,
+			opt:      &commandline.Option{},
+			expected: true,
+		},
+		{
+			name: "Generated file with IncludeGeneratedFlag",
+			input: `// Code generated by protoc-gen-go. DO NOT EDIT.
+
+package main
+
+import "fmt"
+`,
+			opt:      &commandline.Option{IncludeGeneratedFlag: true},
+			expected: false,
+		},
+		{
+			name: "Ignore directive in header",
+			input: `//goreg:ignore
+
+package main
+
+import "fmt"
+`,
+			opt:      &commandline.Option{IncludeGeneratedFlag: true},
+			expected: true,
+		},
+		{
+			name: "Ignore directive in import block",
+			input: `package main
+
+import (
+	//goreg:ignore
+	"fmt"
+)
+`,
+			opt:      &commandline.Option{},
+			expected: true,
+		},
+		{
+			name: "Directive-like text is not a directive",
+			input: `package main
+
+// see //goreg:ignore in the README
+import "fmt"
+`,
+			opt:      &commandline.Option{},
+			expected: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := core.ShouldSkip([]byte(tc.input), tc.opt); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatImports_FrozenRegion(t *testing.T) {
+	opt := &commandline.Option{
+		ImportOrder: model.DefaultOrder,
+		ModulePath:  "myproject/module",
+	}
+
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "Frozen region at the top",
+			input: `package main
+
+import (
+	//goreg:off
+	_ "myproject/module/register"
+	"os"
+	//goreg:on
+	"myproject/module"
+	"github.com/pkg/errors"
+	"fmt"
+)
+`,
+			expected: `package main
+
+import (
+	//goreg:off
+	_ "myproject/module/register"
+	"os"
+	//goreg:on
+
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"myproject/module"
+)
+`,
+		},
+		{
+			name: "Frozen region at the bottom without on",
+			input: `package main
+
+import (
+	"myproject/module"
+	"fmt"
+
+	//goreg:off
+	"github.com/z/last"
+	"github.com/a/first" // keep me
+)
+`,
+			expected: `package main
+
+import (
+	"fmt"
+
+	"myproject/module"
+
+	//goreg:off
+	"github.com/z/last"
+	"github.com/a/first" // keep me
+)
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := core.FormatImports([]byte(tc.input), opt)
+			if err != nil {
+				t.Fatalf("FormatImports failed: %v", err)
+			}
+			if string(output) != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, output)
+			}
+		})
+	}
+}
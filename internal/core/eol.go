@@ -0,0 +1,53 @@
+package core
+
+import "bytes"
+
+var (
+	utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+	crlf    = []byte("\r\n")
+	lf      = []byte("\n")
+)
+
+// textStyle is the byte-order mark and line ending convention of a file.
+// goreg works on BOM-less, LF-terminated text and restores the style of
+// the input on output.
+type textStyle struct {
+	bom  bool
+	crlf bool
+}
+
+// detectTextStyle reports whether src starts with a UTF-8 BOM and whether
+// its first line ends with CRLF, which then applies to the whole file.
+func detectTextStyle(src []byte) textStyle {
+	style := textStyle{bom: bytes.HasPrefix(src, utf8BOM)}
+	if i := bytes.IndexByte(src, '\n'); i > 0 && src[i-1] == '\r' {
+		style.crlf = true
+	}
+	return style
+}
+
+func (s textStyle) isPlain() bool {
+	return !s.bom && !s.crlf
+}
+
+// normalize strips the BOM and turns CRLF line endings into LF.
+func (s textStyle) normalize(src []byte) []byte {
+	src = bytes.TrimPrefix(src, utf8BOM)
+	if s.crlf {
+		src = bytes.ReplaceAll(src, crlf, lf)
+	}
+	return src
+}
+
+// apply gives src the style s. Every line ending of the result follows s,
+// even where src mixed LF and CRLF.
+func (s textStyle) apply(src []byte) []byte {
+	src = textStyle{bom: true, crlf: true}.normalize(src)
+	if s.crlf {
+		src = bytes.ReplaceAll(src, lf, crlf)
+	}
+	if s.bom {
+		src = append(append([]byte{}, utf8BOM...), src...)
+	}
+	return src
+}
@@ -0,0 +1,94 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/magicdrive/goreg/internal/commandline"
+	"github.com/magicdrive/goreg/internal/core"
+	"github.com/magicdrive/goreg/internal/model"
+)
+
+const bom = "\xEF\xBB\xBF"
+
+func TestFormatImports_TextStyle(t *testing.T) {
+	crlf := func(s string) string {
+		return strings.ReplaceAll(s, "\n", "\r\n")
+	}
+
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "CRLF line endings",
+			input:    crlf(unsortedSource),
+			expected: crlf(sortedSource),
+		},
+		{
+			name:     "UTF-8 BOM",
+			input:    bom + unsortedSource,
+			expected: bom + sortedSource,
+		},
+		{
+			name:     "UTF-8 BOM and CRLF line endings",
+			input:    bom + crlf(unsortedSource),
+			expected: bom + crlf(sortedSource),
+		},
+		{
+			name:     "Mixed line endings follow the first line",
+			input:    strings.Replace(crlf(unsortedSource), "func main() {\r\n", "func main() {\n", 1),
+			expected: crlf(sortedSource),
+		},
+		{
+			name:     "Sorted CRLF input",
+			input:    crlf(sortedSource),
+			expected: crlf(sortedSource),
+		},
+	}
+
+	opt := &commandline.Option{
+		ImportOrder: model.DefaultOrder,
+		ModulePath:  "myproject/module",
+		VerifyFlag:  true,
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := core.FormatImports([]byte(tc.input), opt)
+			if err != nil {
+				t.Fatalf("FormatImports failed: %v", err)
+			}
+			if string(output) != tc.expected {
+				t.Errorf("expected:\n%q\ngot:\n%q", tc.expected, string(output))
+			}
+		})
+	}
+}
+
+func TestApply_GofmtTextStyle(t *testing.T) {
+	input := bom + strings.ReplaceAll(unsortedSource, "\n", "\r\n")
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte(input), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	opt := &commandline.Option{
+		ImportOrder: model.DefaultOrder,
+		ModulePath:  "myproject/module",
+		WriteFlag:   true,
+		GofmtFlag:   true,
+		FileName:    path,
+	}
+
+	if err := core.Apply(opt); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	expected := bom + strings.ReplaceAll(sortedSource, "\n", "\r\n")
+	if got, _ := os.ReadFile(path); string(got) != expected {
+		t.Errorf("expected BOM and CRLF to survive gofmt, got:\n%q", got)
+	}
+}
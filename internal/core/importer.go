@@ -4,130 +4,386 @@ import (
 	"bytes"
 	"fmt"
 	"go/ast"
-	"go/parser"
 	"go/token"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/magicdrive/goreg/internal/commandline"
 	"github.com/magicdrive/goreg/internal/model"
 )
 
+// FormatImports arranges the import declarations of src. Only the package
+// clause and the imports are parsed, and only the import region is
+// rewritten; src is returned as is when its imports are already in order.
+// A UTF-8 BOM and CRLF line endings in src are kept.
 func FormatImports(src []byte, opt *commandline.Option) ([]byte, error) {
+	style := detectTextStyle(src)
+	if style.isPlain() {
+		return formatImports(src, opt)
+	}
+
+	result, err := formatImports(style.normalize(src), opt)
+	if err != nil {
+		return nil, err
+	}
+	if result = style.apply(result); bytes.Equal(result, src) {
+		return src, nil
+	}
+	return result, nil
+}
+
+func formatImports(src []byte, opt *commandline.Option) ([]byte, error) {
 	fset := token.NewFileSet()
-	node, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	node, err := parseImports(fset, src)
 	if err != nil {
 		return nil, err
 	}
 
-	importsMap := make(map[string]model.ImportPack)
-	importGroupMap := map[model.ImportGroup][]string{
-		model.StdLib:       {},
-		model.ThirdParty:   {},
-		model.Local:        {},
-		model.Organization: {},
+	// Only parenthesized declarations are rewritten unless single-line
+	// imports are to be expanded into a block.
+	if opt.SingleImport != model.SingleExpand && !hasParenthesizedImport(node) {
+		return src, nil
 	}
 
+	// Comments WriteImports has no place for would be lost, so blocks
+	// holding one are left as they are.
+	frozen := findFrozenRegions(src, fset, node)
+	if hasLooseComment(node, fset, frozen, opt) {
+		return src, nil
+	}
+	groups := arrangeImports(node, fset, opt, frozen)
+
+	var buf bytes.Buffer
+	buf.WriteString(cgoImportsText(src, fset, node))
+	if single := collapsibleImport(groups, frozen, opt); single != nil {
+		var spec bytes.Buffer
+		WriteImports(fset, &spec, []*model.ImportPack{single}, opt, true)
+		buf.WriteString("import " + strings.TrimPrefix(spec.String(), "\t"))
+		return replaceChangedImports(src, fset, node, buf.Bytes(), opt)
+	}
+	buf.WriteString("import (\n")
+
+	for _, region := range frozen {
+		if region.top {
+			buf.WriteString(region.text + "\n")
+			if len(groups) > 0 {
+				buf.WriteString("\n")
+			}
+		}
+	}
+
+	for i, group := range groups {
+		isLastGroup := (i == len(groups)-1)
+		if opt.EmitGroupHeadersFlag && (i == 0 || groups[i-1][0].Group != group[0].Group) {
+			buf.WriteString("\t// " + opt.GroupHeaders.Text(group[0].Group) + "\n")
+		}
+		WriteImports(fset, &buf, group, opt, isLastGroup)
+	}
+
+	for _, region := range frozen {
+		if !region.top {
+			buf.WriteString("\n" + region.text + "\n")
+		}
+	}
+
+	buf.WriteString(")\n")
+	return replaceChangedImports(src, fset, node, buf.Bytes(), opt)
+}
+
+// replaceChangedImports puts newImports in place of the import declarations
+// of src, verifying the result when asked to.
+func replaceChangedImports(src []byte, fset *token.FileSet, node *ast.File, newImports []byte, opt *commandline.Option) ([]byte, error) {
+	if isImportBlockUnchanged(src, fset, node, newImports) {
+		return src, nil
+	}
+	result := replaceImportDecls(src, fset, node, string(newImports))
+
+	if opt.VerifyFlag {
+		if err := VerifyImports(src, result, opt); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// collapsibleImport returns the import to write as `import "fmt"` under
+// single_import = "collapse": the only one of the block, provided no comment
+// sits on a line of its own.
+func collapsibleImport(groups [][]*model.ImportPack, frozen []frozenRegion, opt *commandline.Option) *model.ImportPack {
+	if opt.SingleImport != model.SingleCollapse || len(frozen) > 0 || len(groups) != 1 || len(groups[0]) != 1 {
+		return nil
+	}
+	single := groups[0][0]
+	if len(single.Doc) > 0 || (single.LineComment != nil && IsCommentBeforeImport(single.Entity, single.LineComment)) {
+		return nil
+	}
+	return single
+}
+
+// ExplainImports reports, for every import spec in source order, the group
+// and rule chosen by ClassifyImport and the 1-based position it ends up at.
+func ExplainImports(src []byte, opt *commandline.Option) ([]model.ImportExplanation, error) {
+	fset := token.NewFileSet()
+	node, err := parseImports(fset, src)
+	if err != nil {
+		return nil, err
+	}
+
+	frozen := findFrozenRegions(src, fset, node)
+	groups := arrangeImports(node, fset, opt, frozen)
+
+	positions := make(map[*ast.ImportSpec]int)
+	position := 1
+	addFrozen := func(top bool) {
+		for _, region := range frozen {
+			if region.top != top {
+				continue
+			}
+			for _, spec := range region.specs {
+				positions[spec] = position
+				position++
+			}
+		}
+	}
+
+	addFrozen(true)
+	for _, group := range groups {
+		for _, importPack := range group {
+			positions[importPack.Entity] = position
+			position++
+		}
+	}
+	addFrozen(false)
+
+	result := make([]model.ImportExplanation, 0, len(node.Imports))
+	for _, imp := range node.Imports {
+		path := importPath(imp)
+
+		var alias string
+		if imp.Name != nil {
+			alias = imp.Name.Name
+		}
+		group, rule := classifySpec(path, alias, opt)
+
+		result = append(result, model.ImportExplanation{
+			Path:     path,
+			Alias:    alias,
+			Line:     fset.Position(imp.Pos()).Line,
+			Group:    group,
+			Rule:     rule,
+			Position: positions[imp],
+		})
+	}
+
+	return result, nil
+}
+
+// arrangeImports groups and sorts the imports of node that are not inside a
+// frozen region, returning the non-empty groups in output order.
+func arrangeImports(node *ast.File, fset *token.FileSet,
+	opt *commandline.Option, frozen []frozenRegion) [][]*model.ImportPack {
+	importGroupMap := map[model.ImportGroup][]*model.ImportPack{}
+
 	lineComments := ExtractLineComments(node, fset, opt)
+	declDocs := movedDeclDocs(node)
 
 	for _, imp := range node.Imports {
-		path := strings.Trim(imp.Path.Value, `"`)
-		group := GetImportGroup(path, opt)
+		if inFrozenRegion(frozen, imp) || isCgoImport(imp) {
+			continue
+		}
 
+		path := importPath(imp)
 		docComments, endComment, moduleAlias := ExtractComments(imp, opt)
+		if doc := declDocs[imp]; doc != nil && !opt.RemoveImportCommentFlag {
+			docComments = append(filterDocComments(doc, opt), docComments...)
+		}
+		group, _ := classifySpec(path, moduleAlias, opt)
 		line := fset.Position(imp.Pos()).Line
 		lineComment := lineComments[line]
 
-		importsMap[path] = model.ImportPack{
+		importPack := &model.ImportPack{
 			Entity:      imp,
 			LineComment: lineComment,
 			Doc:         docComments,
 			End:         endComment,
 			Alias:       moduleAlias,
+			Path:        path,
+			Placement:   opt.AliasPlacement.Placement(model.KindOf(moduleAlias), opt.SortIncludeAliasFlag),
 		}
+		importPack.Pin, importPack.PinIndex = model.FindPin(moduleAlias, path, opt.Pinned, opt.PinnedBottom)
+		importPack.Group = group
+
+		importGroupMap[group] = append(importGroupMap[group], importPack)
+	}
 
-		switch group {
-		case model.StdLib:
-			importGroupMap[model.StdLib] = append(importGroupMap[model.StdLib], path)
-		case model.ThirdParty:
-			importGroupMap[model.ThirdParty] = append(importGroupMap[model.ThirdParty], path)
-		case model.Local:
-			importGroupMap[model.Local] = append(importGroupMap[model.Local], path)
-		case model.Organization:
-			importGroupMap[model.Organization] = append(importGroupMap[model.Organization], path)
+	groups := [][]*model.ImportPack{}
+
+	order := model.WithOwnGroups(opt.ImportOrder, opt.AliasPlacement, opt.SortIncludeAliasFlag)
+	for _, elem := range order {
+		if elem == model.ThirdParty && opt.ThirdPartySubgroup != model.SubgroupNone {
+			for _, block := range splitThirdParty(importGroupMap[elem], opt) {
+				sortImports(block, opt)
+				groups = append(groups, block)
+			}
+			continue
+		}
+		if len(importGroupMap[elem]) > 0 {
+			sortImports(importGroupMap[elem], opt)
+			groups = append(groups, importGroupMap[elem])
 		}
 	}
 
-	sortImports(importGroupMap[model.StdLib], importsMap, opt)
-	sortImports(importGroupMap[model.ThirdParty], importsMap, opt)
-	sortImports(importGroupMap[model.Local], importsMap, opt)
-	sortImports(importGroupMap[model.Organization], importsMap, opt)
+	return groups
+}
 
-	var buf bytes.Buffer
-	buf.WriteString("import (\n")
+// hasLooseComment reports whether a parenthesized import declaration of
+// node holds a comment that is not written back with a spec, such as one
+// after the last spec, one standing apart between specs, or one in a block
+// without specs. Directives, group headers and frozen regions are written
+// back on their own, and comments are not kept at all with
+// RemoveImportCommentFlag.
+func hasLooseComment(node *ast.File, fset *token.FileSet, frozen []frozenRegion, opt *commandline.Option) bool {
+	if opt.RemoveImportCommentFlag {
+		return false
+	}
 
-	groups := [][]string{}
+	attached := make(map[*ast.Comment]bool)
+	specStarts := make(map[int]token.Pos)
+	for _, imp := range node.Imports {
+		for _, group := range []*ast.CommentGroup{imp.Doc, imp.Comment} {
+			if group == nil {
+				continue
+			}
+			for _, c := range group.List {
+				attached[c] = true
+			}
+		}
+		specStarts[fset.Position(imp.Pos()).Line] = imp.Pos()
+	}
 
-	for _, elem := range opt.ImportOrder {
-		if len(importGroupMap[elem]) > 0 {
-			groups = append(groups, importGroupMap[elem])
+	for _, decl := range importDecls(node) {
+		if !decl.Lparen.IsValid() {
+			continue
+		}
+		for _, group := range node.Comments {
+			for _, c := range group.List {
+				if c.Pos() < decl.Lparen || c.End() > decl.Rparen || attached[c] ||
+					isGoregDirective(c.Text) || isGroupHeader(c.Text, opt) || inFrozenText(frozen, c) {
+					continue
+				}
+				// A comment in front of a spec on its line goes along with it.
+				if start, ok := specStarts[fset.Position(c.Pos()).Line]; ok && c.Pos() < start {
+					continue
+				}
+				return true
+			}
 		}
 	}
+	return false
+}
 
-	for i, group := range groups {
-		isLastGroup := (i == len(groups)-1)
-		WriteImports(fset, &buf, group, importsMap, opt, isLastGroup)
+// classifySpec is ClassifyImport for an import with the given alias: blank,
+// dot and aliased imports placed in their own group land there instead.
+func classifySpec(path, alias string, opt *commandline.Option) (model.ImportGroup, model.ImportRule) {
+	group, rule := ClassifyImport(path, opt)
+	if group == model.Cgo {
+		return group, rule
 	}
 
-	buf.WriteString(")\n")
-	return ReplaceImports(src, buf.String()), nil
+	kind := model.KindOf(alias)
+	if kind != model.KindPlain && opt.AliasPlacement.Placement(kind, opt.SortIncludeAliasFlag) == model.PlaceOwnGroup {
+		return model.OwnGroupOf(kind), model.RuleOwnGroup
+	}
+	return group, rule
+}
+
+func importPath(imp *ast.ImportSpec) string {
+	if path, err := strconv.Unquote(imp.Path.Value); err == nil {
+		return path
+	}
+	return strings.Trim(imp.Path.Value, "`\"")
 }
 
 func GetImportGroup(pkg string, opt *commandline.Option) model.ImportGroup {
+	group, _ := ClassifyImport(pkg, opt)
+	return group
+}
+
+// ClassifyImport returns the group of pkg together with the rule that matched.
+func ClassifyImport(pkg string, opt *commandline.Option) (model.ImportGroup, model.ImportRule) {
+	if pkg == cgoPath {
+		return model.Cgo, model.RuleCgo
+	}
 	if strings.HasPrefix(pkg, opt.ModulePath) {
-		return model.Local
+		return model.Local, model.RuleLocalModule
 	}
 	if opt.OrganizationName != "" && strings.HasPrefix(pkg, opt.OrganizationName) {
-		return model.Organization
+		return model.Organization, model.RuleOrganization
 	}
 	if !strings.Contains(pkg, ".") {
-		return model.StdLib
+		return model.StdLib, model.RuleStdLib
 	}
-	return model.ThirdParty
+	return model.ThirdParty, model.RuleThirdParty
 }
 
-func sortImports(imports []string, importsMap map[string]model.ImportPack, opt *commandline.Option) {
-	var sortArgo func(i, j int) bool
-	if opt.SortIncludeAliasFlag {
-		sortArgo = func(i, j int) bool {
-			return imports[i] < imports[j]
+// sortImports orders a group into its subgroups (see subgroupOf) and sorts
+// each subgroup by the sort strategy. With SortPreserve, imports keep their
+// source order within a subgroup. Pinned imports go first or last, in the
+// order they are listed in.
+func sortImports(imports []*model.ImportPack, opt *commandline.Option) {
+	sort.SliceStable(imports, func(i, j int) bool {
+		pi, pj := pinRank(imports[i]), pinRank(imports[j])
+		if pi != pj {
+			return pi < pj
+		}
+		if imports[i].Pin != model.PinNone {
+			return imports[i].PinIndex < imports[j].PinIndex
 		}
-	} else {
-		sortArgo = func(i, j int) bool {
-			iData, jData := importsMap[imports[i]], importsMap[imports[j]]
-			iAlias, jAlias := iData.Alias != "", jData.Alias != ""
 
-			if iAlias && !jAlias {
-				return false
-			}
-			if !iAlias && jAlias {
-				return true
-			}
-			return imports[i] < imports[j]
+		si, sj := subgroupOf(imports[i]), subgroupOf(imports[j])
+		if si != sj {
+			return si < sj
+		}
+		if opt.SortStrategy == model.SortPreserve {
+			return false
 		}
+		return lessImport(imports[i], imports[j], opt.SortStrategy)
+	})
+}
+
+func pinRank(importPack *model.ImportPack) int {
+	switch importPack.Pin {
+	case model.PinTop:
+		return 0
+	case model.PinBottom:
+		return 2
+	default:
+		return 1
+	}
+}
 
+// subgroupOf ranks the blank-line-separated blocks of a group: imports
+// placed at the start, then the plain and inline ones, then those placed at
+// the end.
+func subgroupOf(importPack *model.ImportPack) int {
+	switch importPack.Placement {
+	case model.PlaceSubgroupStart:
+		return 0
+	case model.PlaceSubgroupEnd:
+		return 2
+	default:
+		return 1
 	}
-	sort.SliceStable(imports, sortArgo)
 }
 
-func WriteImports(fset *token.FileSet, buf *bytes.Buffer, pkgs []string,
-	importsMap map[string]model.ImportPack, opt *commandline.Option, isLastGroup bool) {
+func WriteImports(fset *token.FileSet, buf *bytes.Buffer, pkgs []*model.ImportPack,
+	opt *commandline.Option, isLastGroup bool) {
 	isFirstImport := true
-	isNoneAliasImport := true
-	isNoneAliasImportExist := false
+	isPrevPinned := false
+	subgroup := 0
 
-	for _, imp := range pkgs {
-		importPack := importsMap[imp]
+	for _, importPack := range pkgs {
 		lineBreaked := false
 
 		if !isFirstImport && len(importPack.Doc) > 0 {
@@ -147,18 +403,21 @@ func WriteImports(fset *token.FileSet, buf *bytes.Buffer, pkgs []string,
 			buf.WriteString(fmt.Sprintf("\t%s\n", importPack.LineComment.Text))
 		}
 
-		if importPack.Alias != "" {
-			if isNoneAliasImport && isNoneAliasImportExist && !opt.MinimizeGroupFlag {
-				if !lineBreaked {
-					buf.WriteString("\n")
-					lineBreaked = true
-				}
-				isNoneAliasImport = false
+		// Pinned imports stick to the block next to them.
+		isPinned := importPack.Pin != model.PinNone
+		if s := subgroupOf(importPack); !isPinned && s != subgroup {
+			if !isFirstImport && !lineBreaked && !isPrevPinned && !opt.MinimizeGroupFlag {
+				buf.WriteString("\n")
+				lineBreaked = true
 			}
-			fmt.Fprintf(buf, "\t%s \"%s\"", importPack.Alias, imp)
+			subgroup = s
+		}
+		isPrevPinned = isPinned
+
+		if importPack.Alias != "" {
+			fmt.Fprintf(buf, "\t%s %s", importPack.Alias, importPack.Entity.Path.Value)
 		} else {
-			isNoneAliasImportExist = true
-			fmt.Fprintf(buf, "\t\"%s\"", imp)
+			fmt.Fprintf(buf, "\t%s", importPack.Entity.Path.Value)
 		}
 
 		if importPack.End != "" {
@@ -168,7 +427,7 @@ func WriteImports(fset *token.FileSet, buf *bytes.Buffer, pkgs []string,
 		isFirstImport = false
 	}
 
-	if !isLastGroup {
+	if !isLastGroup && opt.GroupSeparator == model.SeparatorBlank {
 		buf.WriteString("\n")
 	}
 }
@@ -178,12 +437,14 @@ func ExtractComments(imp *ast.ImportSpec, opt *commandline.Option) ([]string, st
 	var endComment, alias string
 
 	if imp.Doc != nil && len(imp.Doc.List) > 0 && !opt.RemoveImportCommentFlag {
-		for _, c := range imp.Doc.List {
-			docComments = append(docComments, c.Text)
-		}
+		docComments = filterDocComments(imp.Doc, opt)
 	}
 	if imp.Comment != nil && len(imp.Comment.List) > 0 && !opt.RemoveImportCommentFlag {
-		endComment = strings.TrimSpace(imp.Comment.List[0].Text)
+		texts := make([]string, 0, len(imp.Comment.List))
+		for _, c := range imp.Comment.List {
+			texts = append(texts, strings.TrimSpace(c.Text))
+		}
+		endComment = strings.Join(texts, " ")
 	}
 	if imp.Name != nil {
 		alias = imp.Name.Name
@@ -192,6 +453,25 @@ func ExtractComments(imp *ast.ImportSpec, opt *commandline.Option) ([]string, st
 	return docComments, endComment, alias
 }
 
+// filterDocComments returns the lines of doc without goreg directives and
+// group headers.
+func filterDocComments(doc *ast.CommentGroup, opt *commandline.Option) []string {
+	var lines []string
+	for _, c := range doc.List {
+		if isGoregDirective(c.Text) || isGroupHeader(c.Text, opt) {
+			continue
+		}
+		lines = append(lines, c.Text)
+	}
+	return lines
+}
+
+// isGroupHeader reports whether text is a group header goreg wrote earlier.
+// It is dropped and written afresh for the group the import lands in.
+func isGroupHeader(text string, opt *commandline.Option) bool {
+	return opt.EmitGroupHeadersFlag && opt.GroupHeaders.IsHeader(text)
+}
+
 func ExtractLineComments(node *ast.File, fset *token.FileSet, opt *commandline.Option) map[int]*ast.Comment {
 
 	if opt.RemoveImportCommentFlag {
@@ -214,40 +494,148 @@ func IsCommentBeforeImport(imp *ast.ImportSpec, comment *ast.Comment) bool {
 	return comment.Pos() < imp.Pos()
 }
 
-func ReplaceImports(src []byte, newImports string) []byte {
-	srcStr := string(src)
-	start := bytes.Index(src, []byte("import ("))
-	if start == -1 {
+// ReplaceImports merges the import declarations of node into newImports.
+// The first import declaration is replaced by newImports and the others are
+// removed together with their doc comment and the blank space in front of
+// them; cgo `import "C"` declarations are kept in place. Offsets come from
+// the parsed declarations, so parentheses or "import (" inside comments and
+// strings are never mistaken for the import block. Files without a
+// parenthesized import declaration are returned unchanged.
+func ReplaceImports(src []byte, fset *token.FileSet, node *ast.File, newImports string) []byte {
+	if !hasParenthesizedImport(node) {
 		return src
 	}
+	return replaceImportDecls(src, fset, node, newImports)
+}
+
+// replaceImportDecls is ReplaceImports for any kind of import declaration.
+func replaceImportDecls(src []byte, fset *token.FileSet, node *ast.File, newImports string) []byte {
+	decls := importDecls(node)
+	file := fset.File(node.Package)
+
+	var builder strings.Builder
+	builder.Grow(len(src) + len(newImports))
 
-	end := start
-	bracketCount := 1
+	declDocs := movedDeclDocs(node)
 
-	// len("import (") is 8
-	for i := start + 8; i < len(srcStr); i++ {
-		switch srcStr[i] {
-		case '(':
-			bracketCount++
-		case ')':
-			bracketCount--
-			if bracketCount == 0 {
-				end = i + 1
-				goto replace
+	last := 0
+	for i, decl := range decls {
+		start := file.Offset(decl.Pos())
+		end, split := declEnd(src, file, decl)
+		if decl.Doc != nil && len(decl.Specs) > 0 && declDocs[decl.Specs[0].(*ast.ImportSpec)] == decl.Doc {
+			start = file.Offset(decl.Doc.Pos())
+		}
+
+		if i == 0 {
+			builder.Write(src[last:start])
+			builder.WriteString(strings.TrimSuffix(newImports, "\n"))
+		} else {
+			for start > last && isBlank(src[start-1]) {
+				start--
 			}
+			builder.Write(src[last:start])
 		}
+		if split && i == len(decls)-1 {
+			builder.WriteString("\n")
+		}
+		last = end
 	}
+	builder.Write(src[last:])
+
+	return []byte(builder.String())
+}
 
-replace:
-	if end < len(srcStr) && srcStr[end] == '\n' {
-		end++
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// isImportBlockUnchanged reports whether node has a single parenthesized
+// import declaration that already reads exactly like newImports.
+func isImportBlockUnchanged(src []byte, fset *token.FileSet, node *ast.File, newImports []byte) bool {
+	decls := importDecls(node)
+	if len(decls) != 1 {
+		return false
 	}
 
-	var builder strings.Builder
-	builder.Grow(len(srcStr) + len(newImports))
-	builder.WriteString(srcStr[:start])
-	builder.WriteString(newImports)
-	builder.WriteString(srcStr[end:])
+	file := fset.File(node.Package)
+	start := file.Offset(decls[0].Pos())
+	end, _ := declEnd(src, file, decls[0])
+	return bytes.Equal(src[start:end], bytes.TrimSuffix(newImports, []byte("\n")))
+}
 
-	return []byte(builder.String())
+// hasParenthesizedImport reports whether node has an `import ( ... )`
+// declaration other than a cgo one.
+func hasParenthesizedImport(node *ast.File) bool {
+	for _, decl := range importDecls(node) {
+		if decl.Lparen.IsValid() {
+			return true
+		}
+	}
+	return false
+}
+
+// movedDeclDocs returns the doc comments of the import declarations merged
+// into the first one, keyed by the spec they move to: the first spec of
+// their declaration. The doc of the first declaration stays where it is.
+func movedDeclDocs(node *ast.File) map[*ast.ImportSpec]*ast.CommentGroup {
+	docs := make(map[*ast.ImportSpec]*ast.CommentGroup)
+	decls := importDecls(node)
+	for i := 1; i < len(decls); i++ {
+		if decls[i].Doc == nil || len(decls[i].Specs) == 0 {
+			continue
+		}
+		if spec := decls[i].Specs[0].(*ast.ImportSpec); !isCgoImport(spec) {
+			docs[spec] = decls[i].Doc
+		}
+	}
+	return docs
+}
+
+// declEnd returns the offset of the end of decl, including the line comment
+// of a single-line declaration, which moves into the block with its spec,
+// and a `;` separating it from what follows on the same line. split reports
+// whether code follows on that line, which then has to start a new one.
+func declEnd(src []byte, file *token.File, decl *ast.GenDecl) (end int, split bool) {
+	pos := decl.End()
+	if !decl.Lparen.IsValid() && len(decl.Specs) == 1 {
+		if c := decl.Specs[0].(*ast.ImportSpec).Comment; c != nil && c.End() > pos {
+			pos = c.End()
+		}
+	}
+	return skipSemicolon(src, file.Offset(pos))
+}
+
+// skipSemicolon returns the offset after the `;` following offset on the
+// same line, or offset when there is none. When code follows the `;` on
+// the line, the blanks in front of it are skipped as well and split is true.
+func skipSemicolon(src []byte, offset int) (end int, split bool) {
+	i := skipSpaces(src, offset)
+	if i == len(src) || src[i] != ';' {
+		return offset, false
+	}
+
+	j := skipSpaces(src, i+1)
+	if j == len(src) || src[j] == '\n' || bytes.HasPrefix(src[j:], []byte("//")) || bytes.HasPrefix(src[j:], []byte("/*")) {
+		return i + 1, false
+	}
+	return j, true
+}
+
+func skipSpaces(src []byte, offset int) int {
+	for offset < len(src) && (src[offset] == ' ' || src[offset] == '\t') {
+		offset++
+	}
+	return offset
+}
+
+// importDecls returns the import declarations of node that goreg rewrites,
+// which is all of them but the cgo ones.
+func importDecls(node *ast.File) []*ast.GenDecl {
+	var decls []*ast.GenDecl
+	for _, decl := range node.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT && !isCgoDecl(genDecl) {
+			decls = append(decls, genDecl)
+		}
+	}
+	return decls
 }
@@ -2,6 +2,7 @@ package core_test
 
 import (
 	"bytes"
+	"fmt"
 	"testing"
 
 	"github.com/magicdrive/goreg/internal/commandline"
@@ -236,11 +237,227 @@ import (
 				RemoveImportCommentFlag: true,
 			},
 		},
+		{
+			name: "Import block mentioned in package doc comment",
+			input: `// Package main shows how to write
+// import (
+//	"fmt"
+// ) in a doc comment.
+package main
+
+import (
+	"os"
+	"fmt"
+)
+`,
+			expected: `// Package main shows how to write
+// import (
+//	"fmt"
+// ) in a doc comment.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+`,
+			wantErr: false,
+			opt: &commandline.Option{
+				ImportOrder: model.DefaultOrder,
+				ModulePath:  "myproject/module",
+			},
+		},
+		{
+			name: "Parentheses inside import comments",
+			input: `package main
+
+import (
+	"os" // see (x
+	"fmt" // close ) early
+)
+
+func main() { fmt.Println(os.Args) }
+`,
+			expected: `package main
+
+import (
+	"fmt" // close ) early
+	"os" // see (x
+)
+
+func main() { fmt.Println(os.Args) }
+`,
+			wantErr: false,
+			opt: &commandline.Option{
+				ImportOrder: model.DefaultOrder,
+				ModulePath:  "myproject/module",
+			},
+		},
+		{
+			name: "Same path imported twice",
+			input: `package main
+
+import (
+	b "github.com/pkg/errors"
+	a "github.com/pkg/errors"
+	"fmt"
+)
+`,
+			expected: `package main
+
+import (
+	"fmt"
+
+	a "github.com/pkg/errors"
+	b "github.com/pkg/errors"
+)
+`,
+			wantErr: false,
+			opt: &commandline.Option{
+				ImportOrder: model.DefaultOrder,
+				ModulePath:  "myproject/module",
+			},
+		},
+		{
+			name: "Multiple import declarations are merged",
+			input: `package main
+
+import "os"
+
+import (
+	"github.com/pkg/errors"
+	"fmt"
+)
+
+var _ = errors.New
+`,
+			expected: `package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+var _ = errors.New
+`,
+			wantErr: false,
+			opt: &commandline.Option{
+				ImportOrder: model.DefaultOrder,
+				ModulePath:  "myproject/module",
+			},
+		},
+		{
+			name: "Merged declaration keeps its trailing comment once",
+			input: `package main
+
+import (
+	"os"
+)
+
+import "fmt" // printing
+
+func main() {}
+`,
+			expected: `package main
+
+import (
+	"fmt" // printing
+	"os"
+)
+
+func main() {}
+`,
+			wantErr: false,
+			opt: &commandline.Option{
+				ImportOrder: model.DefaultOrder,
+				ModulePath:  "myproject/module",
+			},
+		},
+		{
+			name: "Merged declaration brings its doc comment along",
+			input: `package main
+
+import (
+	"os"
+)
+
+// needed for fmt
+import "fmt"
+
+func main() {}
+`,
+			expected: `package main
+
+import (
+	// needed for fmt
+	"fmt"
+	"os"
+)
+
+func main() {}
+`,
+			wantErr: false,
+			opt: &commandline.Option{
+				ImportOrder: model.DefaultOrder,
+				ModulePath:  "myproject/module",
+			},
+		},
+		{
+			name: "Parenthesized declarations on one line",
+			input: `package main
+
+import ("os"); import ("fmt")
+
+func main() {}
+`,
+			expected: `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {}
+`,
+			wantErr: false,
+			opt: &commandline.Option{
+				ImportOrder: model.DefaultOrder,
+				ModulePath:  "myproject/module",
+			},
+		},
+		{
+			name: "Bare declaration sharing a line with a block",
+			input: `package main
+
+import "os"; import ("fmt")
+
+func main() {}
+`,
+			expected: `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {}
+`,
+			wantErr: false,
+			opt: &commandline.Option{
+				ImportOrder: model.DefaultOrder,
+				ModulePath:  "myproject/module",
+			},
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			output, err := core.FormatImports([]byte(tc.input), tc.opt)
+			opt := *tc.opt
+			opt.VerifyFlag = true
+
+			output, err := core.FormatImports([]byte(tc.input), &opt)
 			if (err != nil) != tc.wantErr {
 				t.Fatalf("unexpected error status: %v", err)
 			}
@@ -255,3 +472,745 @@ import (
 		})
 	}
 }
+
+func TestExplainImports(t *testing.T) {
+	input := `package main
+
+import (
+	"myproject/module"
+	mylog "log"
+	"github.com/pkg/errors"
+	"orgname/project"
+	"fmt"
+)
+`
+	opt := &commandline.Option{
+		ImportOrder:      model.DefaultOrder,
+		OrganizationName: "orgname",
+		ModulePath:       "myproject/module",
+	}
+
+	expected := []model.ImportExplanation{
+		{Path: "myproject/module", Line: 4, Group: model.Local, Rule: model.RuleLocalModule, Position: 5},
+		{Path: "log", Alias: "mylog", Line: 5, Group: model.StdLib, Rule: model.RuleStdLib, Position: 2},
+		{Path: "github.com/pkg/errors", Line: 6, Group: model.ThirdParty, Rule: model.RuleThirdParty, Position: 3},
+		{Path: "orgname/project", Line: 7, Group: model.Organization, Rule: model.RuleOrganization, Position: 4},
+		{Path: "fmt", Line: 8, Group: model.StdLib, Rule: model.RuleStdLib, Position: 1},
+	}
+
+	got, err := core.ExplainImports([]byte(input), opt)
+	if err != nil {
+		t.Fatalf("ExplainImports failed: %v", err)
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d explanations, got %d", len(expected), len(got))
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("explanation %d: expected %+v, got %+v", i, expected[i], got[i])
+		}
+	}
+}
+
+func TestFormatImports_Unchanged(t *testing.T) {
+	src := []byte(`package main
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+func main() { fmt.Println(errors.New("x")) }
+`)
+	opt := &commandline.Option{
+		ImportOrder: model.DefaultOrder,
+		ModulePath:  "myproject/module",
+		VerifyFlag:  true,
+	}
+
+	output, err := core.FormatImports(src, opt)
+	if err != nil {
+		t.Fatalf("FormatImports failed: %v", err)
+	}
+	if &output[0] != &src[0] {
+		t.Errorf("expected sorted input to be returned without rewriting")
+	}
+}
+
+func TestFormatImports_LooseComments(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+	}{
+		{
+			name: "Block holding only a comment",
+			input: `package main
+
+import (
+	// only comment
+)
+
+func main() {}
+`,
+		},
+		{
+			name: "Comment after the last import",
+			input: `package main
+
+import (
+	"os"
+	"fmt"
+	// trailing
+)
+
+func main() {}
+`,
+		},
+		{
+			name: "Comment standing apart between imports",
+			input: `package main
+
+import (
+	"os"
+
+	// floating
+
+	"fmt"
+)
+
+func main() {}
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		for _, verify := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/verify=%v", tc.name, verify), func(t *testing.T) {
+				opt := &commandline.Option{
+					ImportOrder: model.DefaultOrder,
+					ModulePath:  "myproject/module",
+					VerifyFlag:  verify,
+				}
+
+				output, err := core.FormatImports([]byte(tc.input), opt)
+				if err != nil {
+					t.Fatalf("FormatImports failed: %v", err)
+				}
+				if string(output) != tc.input {
+					t.Errorf("expected the input back, got:\n%s", string(output))
+				}
+			})
+		}
+	}
+}
+
+func TestFormatImports_SyntaxErrors(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name: "Half-typed function body",
+			input: `package main
+
+import (
+	"os"
+	"fmt"
+)
+
+func main() {
+	fmt.Println(os.
+`,
+			expected: `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	fmt.Println(os.
+`,
+		},
+		{
+			name: "Unterminated comment after imports",
+			input: `package main
+
+import (
+	"os"
+	"fmt"
+)
+
+/* unfinished
+`,
+			expected: `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+/* unfinished
+`,
+		},
+		{
+			name: "Unterminated import block",
+			input: `package main
+
+import (
+	"os"
+	"fmt"
+
+func main() {}
+`,
+			wantErr: true,
+		},
+		{
+			name: "Unterminated import path",
+			input: `package main
+
+import (
+	"os"
+	"fmt
+)
+`,
+			wantErr: true,
+		},
+	}
+
+	opt := &commandline.Option{
+		ImportOrder: model.DefaultOrder,
+		ModulePath:  "myproject/module",
+		VerifyFlag:  true,
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := core.FormatImports([]byte(tc.input), opt)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error status: %v", err)
+			}
+			if tc.wantErr {
+				return
+			}
+			if string(output) != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, string(output))
+			}
+		})
+	}
+}
+
+func TestFormatImports_AliasPlacement(t *testing.T) {
+	input := `package main
+
+import (
+	_ "embed"
+	"os"
+	. "github.com/onsi/gomega"
+	pkgerrors "github.com/pkg/errors"
+	_ "github.com/lib/pq"
+	"github.com/google/uuid"
+	"fmt"
+)
+`
+
+	cases := []struct {
+		name      string
+		order     string
+		placement model.AliasPlacementConfig
+		expected  string
+	}{
+		{
+			name: "Default places every alias at the end of its group",
+			expected: `package main
+
+import (
+	"fmt"
+	"os"
+
+	_ "embed"
+
+	"github.com/google/uuid"
+
+	_ "github.com/lib/pq"
+	. "github.com/onsi/gomega"
+	pkgerrors "github.com/pkg/errors"
+)
+`,
+		},
+		{
+			name:      "Blank imports in their own group",
+			placement: model.AliasPlacementConfig{Blank: "own-group"},
+			expected: `package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+
+	. "github.com/onsi/gomega"
+	pkgerrors "github.com/pkg/errors"
+
+	_ "embed"
+	_ "github.com/lib/pq"
+)
+`,
+		},
+		{
+			name:      "Own group positioned by order",
+			order:     "std,blank,thirdparty,organization,local",
+			placement: model.AliasPlacementConfig{Blank: "own-group"},
+			expected: `package main
+
+import (
+	"fmt"
+	"os"
+
+	_ "embed"
+	_ "github.com/lib/pq"
+
+	"github.com/google/uuid"
+
+	. "github.com/onsi/gomega"
+	pkgerrors "github.com/pkg/errors"
+)
+`,
+		},
+		{
+			name:      "Mixed placements",
+			placement: model.AliasPlacementConfig{Blank: "subgroup-start", Dot: "subgroup-end", Alias: "inline"},
+			expected: `package main
+
+import (
+	_ "embed"
+
+	"fmt"
+	"os"
+
+	_ "github.com/lib/pq"
+
+	"github.com/google/uuid"
+	pkgerrors "github.com/pkg/errors"
+
+	. "github.com/onsi/gomega"
+)
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			order := model.DefaultOrder
+			if tc.order != "" {
+				var err error
+				if order, err = model.GenerateOrderStrings(tc.order); err != nil {
+					t.Fatalf("invalid order: %v", err)
+				}
+			}
+			opt := &commandline.Option{
+				ImportOrder:    order,
+				ModulePath:     "myproject/module",
+				AliasPlacement: tc.placement,
+				VerifyFlag:     true,
+			}
+
+			output, err := core.FormatImports([]byte(input), opt)
+			if err != nil {
+				t.Fatalf("FormatImports failed: %v", err)
+			}
+			if string(output) != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, string(output))
+			}
+		})
+	}
+}
+
+func TestFormatImports_Pinned(t *testing.T) {
+	input := `package main
+
+import (
+	"unsafe"
+	"os"
+	"context"
+	_ "embed"
+	"fmt"
+	mylog "log"
+	"github.com/pkg/errors"
+)
+`
+
+	cases := []struct {
+		name     string
+		pinned   []string
+		bottom   []string
+		expected string
+	}{
+		{
+			name:   "Pinned to the top in listed order",
+			pinned: []string{`_ "embed"`, "context"},
+			expected: `package main
+
+import (
+	_ "embed"
+	"context"
+	"fmt"
+	"os"
+	"unsafe"
+
+	mylog "log"
+
+	"github.com/pkg/errors"
+)
+`,
+		},
+		{
+			name:   "Pinned to the bottom",
+			bottom: []string{`"unsafe"`},
+			expected: `package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	_ "embed"
+	mylog "log"
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+`,
+		},
+		{
+			name:   "Alias must match",
+			pinned: []string{`_ "log"`, `mylog "log"`},
+			expected: `package main
+
+import (
+	mylog "log"
+	"context"
+	"fmt"
+	"os"
+	"unsafe"
+
+	_ "embed"
+
+	"github.com/pkg/errors"
+)
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			opt := &commandline.Option{
+				ImportOrder:  model.DefaultOrder,
+				ModulePath:   "myproject/module",
+				Pinned:       tc.pinned,
+				PinnedBottom: tc.bottom,
+				VerifyFlag:   true,
+			}
+
+			output, err := core.FormatImports([]byte(input), opt)
+			if err != nil {
+				t.Fatalf("FormatImports failed: %v", err)
+			}
+			if string(output) != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, string(output))
+			}
+		})
+	}
+}
+
+func TestFormatImports_GroupHeaders(t *testing.T) {
+	expected := `package main
+
+import (
+	// Standard library
+	"fmt"
+	"os"
+
+	// Third-party
+	"github.com/pkg/errors"
+
+	// Our code
+	"myproject/module/internal/util"
+)
+`
+
+	cases := []struct {
+		name  string
+		input string
+	}{
+		{
+			name: "Headers are added",
+			input: `package main
+
+import (
+	"os"
+	"myproject/module/internal/util"
+	"github.com/pkg/errors"
+	"fmt"
+)
+`,
+		},
+		{
+			name: "Stale headers are replaced",
+			input: `package main
+
+import (
+	// Third-party
+	"myproject/module/internal/util"
+	"os"
+
+	// Local
+	"github.com/pkg/errors"
+	"fmt"
+)
+`,
+		},
+		{
+			name:  "Output is stable",
+			input: expected,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			opt := &commandline.Option{
+				ImportOrder:          model.DefaultOrder,
+				ModulePath:           "myproject/module",
+				EmitGroupHeadersFlag: true,
+				GroupHeaders:         model.GroupHeadersConfig{Local: "Our code"},
+				VerifyFlag:           true,
+			}
+
+			output, err := core.FormatImports([]byte(tc.input), opt)
+			if err != nil {
+				t.Fatalf("FormatImports failed: %v", err)
+			}
+			if string(output) != expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", expected, string(output))
+			}
+		})
+	}
+}
+
+func TestFormatImports_Layout(t *testing.T) {
+	cases := []struct {
+		name      string
+		separator model.GroupSeparator
+		single    model.SingleImport
+		input     string
+		expected  string
+	}{
+		{
+			name:      "No separator between groups",
+			separator: model.SeparatorNone,
+			input: `package main
+
+import (
+	"github.com/pkg/errors"
+
+	"fmt"
+)
+`,
+			expected: `package main
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+)
+`,
+		},
+		{
+			name:   "Preserve keeps a one-import block",
+			single: model.SinglePreserve,
+			input: `package main
+
+import (
+	"fmt"
+)
+`,
+			expected: `package main
+
+import (
+	"fmt"
+)
+`,
+		},
+		{
+			name:   "Preserve keeps single-line imports",
+			single: model.SinglePreserve,
+			input: `package main
+
+import "os"
+import "fmt"
+`,
+			expected: `package main
+
+import "os"
+import "fmt"
+`,
+		},
+		{
+			name:   "Collapse a one-import block",
+			single: model.SingleCollapse,
+			input: `package main
+
+import (
+	f "fmt" // printing
+)
+`,
+			expected: `package main
+
+import f "fmt" // printing
+`,
+		},
+		{
+			name:   "Collapse keeps a block with a doc comment",
+			single: model.SingleCollapse,
+			input: `package main
+
+import (
+	// printing
+	"fmt"
+)
+`,
+			expected: `package main
+
+import (
+	// printing
+	"fmt"
+)
+`,
+		},
+		{
+			name:   "Collapse keeps a block of several imports",
+			single: model.SingleCollapse,
+			input: `package main
+
+import (
+	"os"
+	"fmt"
+)
+`,
+			expected: `package main
+
+import (
+	"fmt"
+	"os"
+)
+`,
+		},
+		{
+			name:   "Expand keeps a trailing comment once",
+			single: model.SingleExpand,
+			input: `package main
+
+import "fmt" // hi
+
+func main() {}
+`,
+			expected: `package main
+
+import (
+	"fmt" // hi
+)
+
+func main() {}
+`,
+		},
+		{
+			name:   "Expand declarations sharing a line",
+			single: model.SingleExpand,
+			input: `package main
+
+import "fmt"; import "os"
+
+func main() {}
+`,
+			expected: `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {}
+`,
+		},
+		{
+			name:   "Expand moves code after the declaration to a new line",
+			single: model.SingleExpand,
+			input: `package main
+
+import "fmt"; var x = 1
+
+func main() { fmt.Println(x) }
+`,
+			expected: `package main
+
+import (
+	"fmt"
+)
+var x = 1
+
+func main() { fmt.Println(x) }
+`,
+		},
+		{
+			name:   "Expand single-line imports",
+			single: model.SingleExpand,
+			input: `package main
+
+import "os"
+import "fmt"
+
+func main() {}
+`,
+			expected: `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {}
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			opt := &commandline.Option{
+				ImportOrder:    model.DefaultOrder,
+				ModulePath:     "myproject/module",
+				GroupSeparator: tc.separator,
+				SingleImport:   tc.single,
+				VerifyFlag:     true,
+			}
+
+			output, err := core.FormatImports([]byte(tc.input), opt)
+			if err != nil {
+				t.Fatalf("FormatImports failed: %v", err)
+			}
+			if string(output) != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, string(output))
+			}
+
+			again, err := core.FormatImports(output, opt)
+			if err != nil {
+				t.Fatalf("FormatImports failed on its own output: %v", err)
+			}
+			if string(again) != string(output) {
+				t.Errorf("output is not stable:\n%s", string(again))
+			}
+		})
+	}
+}
@@ -1,30 +1,141 @@
 package core
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"golang.org/x/tools/imports"
 
+	"github.com/magicdrive/goreg/internal/cache"
 	"github.com/magicdrive/goreg/internal/commandline"
+	"github.com/magicdrive/goreg/internal/common"
 )
 
 func Apply(opt *commandline.Option) error {
-	filename := opt.FileName
+	info, err := os.Stat(opt.FileName)
+	if err != nil {
+		return err
+	}
+
+	if opt.CheckFlag {
+		return checkPath(opt, opt.FileName, info.IsDir())
+	}
+
+	var c *cache.Cache
+	if !opt.NoCacheFlag && opt.CacheDir != "" {
+		c = cache.Open(opt.CacheDir)
+	}
+
+	if info.IsDir() {
+		return applyDir(opt, c, opt.FileName)
+	}
+	return applyFile(opt, c, opt.FileName)
+}
+
+// applyDir applies goreg to every .go file below root.
+func applyDir(opt *commandline.Option, c *cache.Cache, root string) error {
+	return WalkGoFiles(opt, root, func(path string) error {
+		return applyFile(opt, c, path)
+	})
+}
+
+// WalkGoFiles calls fn for every .go file below the directory root, skipping
+// files that match the exclude patterns or are ignored by .gitignore. The
+// errors returned by fn are collected with the file name and do not stop
+// the walk.
+func WalkGoFiles(opt *commandline.Option, root string, fn func(path string) error) error {
+	var gitIgnore *common.GitIgnore
+	if !opt.NoGitignoreFlag {
+		var err error
+		if gitIgnore, err = common.NewGitIgnore(root); err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	walkErr := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path == root {
+				return nil
+			}
+			if d.Name() == ".git" || opt.IsExcluded(path) || gitIgnore.Ignored(path, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(path, ".go") || opt.IsExcluded(path) || gitIgnore.Ignored(path, false) {
+			return nil
+		}
+
+		if err := fn(path); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+		return nil
+	})
+	if walkErr != nil {
+		errs = append(errs, walkErr)
+	}
+
+	return errors.Join(errs...)
+}
+
+// applyFile formats filename. Files whose content c already knows to be
+// formatted with the same options are skipped without being parsed.
+func applyFile(opt *commandline.Option, c *cache.Cache, filename string) error {
 	basename := filepath.Base(filename)
 	if basename == "go.mod" || basename == "go.sum" {
 		return nil
 	}
 
+	opt, err := opt.ForFile(filename)
+	if err != nil {
+		return err
+	}
+
 	src, err := os.ReadFile(filename)
 	if err != nil {
 		return err
 	}
 
-	formatted, err := imports.Process(filename, src, &imports.Options{
-		FormatOnly: true,
-		Comments:   true,
-	})
+	key := cache.Key(opt.Version, opt.Fingerprint(), src)
+	if c.Has(key) {
+		if opt.WriteFlag {
+			return nil
+		}
+		_, err := os.Stdout.Write(src)
+		return err
+	}
+
+	if ShouldSkip(src, opt) {
+		if opt.WriteFlag {
+			return nil
+		}
+		_, err := os.Stdout.Write(src)
+		return err
+	}
+
+	// A file that gofmt cannot parse still gets its imports arranged.
+	formatted := src
+	if opt.GofmtFlag {
+		if processed, err := imports.Process(filename, src, &imports.Options{
+			FormatOnly: true,
+			Comments:   true,
+		}); err == nil {
+			formatted = detectTextStyle(src).apply(processed)
+		}
+	}
+
+	formatted, err = RewriteAliases(formatted, opt)
 	if err != nil {
 		return err
 	}
@@ -35,8 +146,16 @@ func Apply(opt *commandline.Option) error {
 	}
 
 	if opt.WriteFlag {
-		return os.WriteFile(filename, sorted, 0644)
+		if err := WriteFileAtomic(filename, src, sorted, opt.BackupSuffix); err != nil {
+			return err
+		}
+		// The cache only saves work, so failing to update it is not an error.
+		_ = c.Put(cache.Key(opt.Version, opt.Fingerprint(), sorted))
+		return nil
 	} else {
+		if bytes.Equal(src, sorted) {
+			_ = c.Put(key)
+		}
 		_, err := os.Stdout.Write(sorted)
 		return err
 	}
@@ -0,0 +1,306 @@
+package core_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/magicdrive/goreg/internal/cache"
+	"github.com/magicdrive/goreg/internal/commandline"
+	"github.com/magicdrive/goreg/internal/core"
+	"github.com/magicdrive/goreg/internal/model"
+)
+
+const unsortedSource = `package main
+
+import (
+	"github.com/pkg/errors"
+	"fmt"
+)
+
+func main() {
+	fmt.Println(errors.New("x"))
+}
+`
+
+const sortedSource = `package main
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+func main() {
+	fmt.Println(errors.New("x"))
+}
+`
+
+func TestApply_Directory(t *testing.T) {
+	root := t.TempDir()
+
+	files := map[string]string{
+		".gitignore":               "ignored/\n",
+		"main.go":                  unsortedSource,
+		"pkg/util.go":              unsortedSource,
+		"vendor/lib/lib.go":        unsortedSource,
+		"api/service.pb.go":        unsortedSource,
+		"ignored/tmp.go":           unsortedSource,
+		"README.md":                unsortedSource,
+		"internal/gen/generate.go": unsortedSource,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+
+	opt := &commandline.Option{
+		ImportOrder: model.DefaultOrder,
+		ModulePath:  "myproject/module",
+		WriteFlag:   true,
+		FileName:    root,
+		ConfigPath:  filepath.Join(root, "goreg.toml"),
+		Exclude:     []string{"vendor/**", "*.pb.go", "internal/gen/**"},
+	}
+
+	if err := core.Apply(opt); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"main.go":                  sortedSource,
+		"pkg/util.go":              sortedSource,
+		"vendor/lib/lib.go":        unsortedSource,
+		"api/service.pb.go":        unsortedSource,
+		"ignored/tmp.go":           unsortedSource,
+		"README.md":                unsortedSource,
+		"internal/gen/generate.go": unsortedSource,
+	}
+	for name, want := range expected {
+		got, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		if string(got) != want {
+			t.Errorf("%s: expected:\n%s\ngot:\n%s", name, want, got)
+		}
+	}
+}
+
+func TestApply_DirectoryNoGitignore(t *testing.T) {
+	root := t.TempDir()
+
+	path := filepath.Join(root, "ignored", "tmp.go")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(unsortedSource), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte("ignored/\n"), 0644); err != nil {
+		t.Fatalf("failed to write .gitignore: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+
+	opt := &commandline.Option{
+		ImportOrder:     model.DefaultOrder,
+		ModulePath:      "myproject/module",
+		WriteFlag:       true,
+		FileName:        root,
+		NoGitignoreFlag: true,
+	}
+
+	if err := core.Apply(opt); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	if string(got) != sortedSource {
+		t.Errorf("expected ignored file to be formatted with NoGitignoreFlag, got:\n%s", got)
+	}
+}
+
+func TestApply_SkipGenerated(t *testing.T) {
+	generated := "// Code generated by mockgen. DO NOT EDIT.\n\n" + unsortedSource
+	path := filepath.Join(t.TempDir(), "mock.go")
+	if err := os.WriteFile(path, []byte(generated), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	opt := &commandline.Option{
+		ImportOrder: model.DefaultOrder,
+		ModulePath:  "myproject/module",
+		WriteFlag:   true,
+		FileName:    path,
+	}
+
+	if err := core.Apply(opt); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != generated {
+		t.Errorf("expected generated file to be left untouched, got:\n%s", got)
+	}
+
+	opt.IncludeGeneratedFlag = true
+	if err := core.Apply(opt); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	expected := "// Code generated by mockgen. DO NOT EDIT.\n\n" + sortedSource
+	if got, _ := os.ReadFile(path); string(got) != expected {
+		t.Errorf("expected generated file to be formatted with IncludeGeneratedFlag, got:\n%s", got)
+	}
+}
+
+func TestApply_Gofmt(t *testing.T) {
+	unformatted := "package main\n\nimport (\n\t\"github.com/pkg/errors\"\n\t\"fmt\"\n)\n\nfunc main()  {\n\tfmt.Println(errors.New(\"x\"))\n}\n"
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte(unformatted), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	opt := &commandline.Option{
+		ImportOrder: model.DefaultOrder,
+		ModulePath:  "myproject/module",
+		WriteFlag:   true,
+		FileName:    path,
+	}
+
+	if err := core.Apply(opt); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	expected := strings.Replace(sortedSource, "func main() {", "func main()  {", 1)
+	if got, _ := os.ReadFile(path); string(got) != expected {
+		t.Errorf("expected only the import block to change, got:\n%s", got)
+	}
+
+	opt.GofmtFlag = true
+	if err := core.Apply(opt); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != sortedSource {
+		t.Errorf("expected the whole file to be formatted with GofmtFlag, got:\n%s", got)
+	}
+}
+
+func TestApply_Cache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte(unsortedSource), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	opt := &commandline.Option{
+		ImportOrder: model.DefaultOrder,
+		ModulePath:  "myproject/module",
+		WriteFlag:   true,
+		FileName:    path,
+		CacheDir:    filepath.Join(t.TempDir(), "goreg"),
+		Version:     "test",
+	}
+	c := cache.Open(opt.CacheDir)
+
+	if err := core.Apply(opt); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !c.Has(cache.Key(opt.Version, opt.Fingerprint(), []byte(sortedSource))) {
+		t.Errorf("expected written output to be recorded in the cache")
+	}
+
+	// A cache hit must skip the file even though it is not formatted.
+	if err := os.WriteFile(path, []byte(unsortedSource), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := c.Put(cache.Key(opt.Version, opt.Fingerprint(), []byte(unsortedSource))); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if err := core.Apply(opt); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != unsortedSource {
+		t.Errorf("expected cached file to be skipped, got:\n%s", got)
+	}
+
+	opt.NoCacheFlag = true
+	if err := core.Apply(opt); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != sortedSource {
+		t.Errorf("expected file to be formatted with NoCacheFlag, got:\n%s", got)
+	}
+}
+
+func TestApply_GofmtSyntaxError(t *testing.T) {
+	broken := strings.Replace(unsortedSource, "fmt.Println(errors.New(\"x\"))", "fmt.Println(errors.New(", 1)
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte(broken), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	opt := &commandline.Option{
+		ImportOrder: model.DefaultOrder,
+		ModulePath:  "myproject/module",
+		WriteFlag:   true,
+		GofmtFlag:   true,
+		FileName:    path,
+	}
+
+	if err := core.Apply(opt); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	expected := strings.Replace(sortedSource, "fmt.Println(errors.New(\"x\"))", "fmt.Println(errors.New(", 1)
+	if got, _ := os.ReadFile(path); string(got) != expected {
+		t.Errorf("expected imports to be arranged despite the syntax error, got:\n%s", got)
+	}
+}
+
+func TestApply_Check(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "main.go")
+	if err := os.WriteFile(path, []byte(unsortedSource), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		deny    []string
+		wantErr error
+	}{
+		{name: "Denied import", deny: []string{"github.com/pkg/errors"}, wantErr: core.ErrCheckFailed},
+		{name: "No violation", deny: []string{"github.com/sirupsen/logrus"}, wantErr: nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			opt := &commandline.Option{
+				ImportOrder: model.DefaultOrder,
+				ModulePath:  "myproject/module",
+				ModuleRoot:  root,
+				Rules:       model.RulesConfig{Deny: tc.deny},
+				CheckFlag:   true,
+				WriteFlag:   true,
+				FileName:    root,
+			}
+
+			if err := core.Apply(opt); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected %v, got %v", tc.wantErr, err)
+			}
+			if got, _ := os.ReadFile(path); string(got) != unsortedSource {
+				t.Errorf("--check must not modify files, got:\n%s", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+)
+
+// parseImports parses the package clause and the import declarations of
+// src. Editors run formatters on half-typed files, so syntax errors found
+// after the last import declaration are tolerated: the imports are valid on
+// their own and goreg never touches the rest of the file.
+func parseImports(fset *token.FileSet, src []byte) (*ast.File, error) {
+	node, err := parser.ParseFile(fset, "", src, parser.ImportsOnly|parser.ParseComments)
+	if err == nil {
+		return node, nil
+	}
+
+	var errList scanner.ErrorList
+	if node == nil || node.Name == nil || !errors.As(err, &errList) {
+		return nil, err
+	}
+
+	end := node.Name.End()
+	for _, decl := range node.Decls {
+		if decl.End() > end {
+			end = decl.End()
+		}
+	}
+	limit := fset.Position(end).Offset
+
+	for _, e := range errList {
+		if e.Pos.Offset < limit {
+			return nil, err
+		}
+	}
+	return node, nil
+}
@@ -0,0 +1,151 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/magicdrive/goreg/internal/commandline"
+	"github.com/magicdrive/goreg/internal/model"
+)
+
+// ErrCheckFailed is returned by --check when an import breaks a [rules]
+// entry.
+var ErrCheckFailed = errors.New("import rules violated")
+
+// Violation is an import breaking a [rules] entry.
+type Violation struct {
+	Position token.Position
+	Message  string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Position, v.Message)
+}
+
+// CheckImports returns the imports of src breaking opt.Rules. filename is
+// used in the positions and to tell the package of the file, which layer
+// rules apply to.
+func CheckImports(filename string, src []byte, opt *commandline.Option) ([]Violation, error) {
+	fset := token.NewFileSet()
+	node, err := parseImports(fset, src)
+	if err != nil {
+		return nil, err
+	}
+
+	pkgPath := packagePath(filename, opt)
+	var violations []Violation
+	report := func(imp token.Pos, message, hint string) {
+		if hint != "" {
+			message += ": " + hint
+		}
+		position := fset.Position(imp)
+		position.Filename = filename
+		violations = append(violations, Violation{Position: position, Message: message})
+	}
+
+	for _, imp := range node.Imports {
+		path := importPath(imp)
+
+		for _, pattern := range opt.Rules.Deny {
+			if model.MatchImportPattern(pattern, path, opt.ModulePath) {
+				report(imp.Path.Pos(), fmt.Sprintf("import %s is denied", strconv.Quote(path)), opt.Rules.Hints[pattern])
+				break
+			}
+		}
+
+		if pkgPath == "" {
+			continue
+		}
+		for _, layer := range opt.Rules.Layers {
+			if !model.MatchImportPattern(layer.From, pkgPath, opt.ModulePath) {
+				continue
+			}
+			for _, pattern := range layer.Deny {
+				if model.MatchImportPattern(pattern, path, opt.ModulePath) {
+					report(imp.Path.Pos(), fmt.Sprintf("package %s may not import %s", pkgPath, strconv.Quote(path)), layer.Hint)
+					break
+				}
+			}
+		}
+	}
+	return violations, nil
+}
+
+// packagePath returns the import path of the package filename belongs to,
+// or "" when it lies outside the local module.
+func packagePath(filename string, opt *commandline.Option) string {
+	if opt.ModulePath == "" {
+		return ""
+	}
+	root := opt.ModuleRoot
+	if root == "" {
+		root = "."
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return ""
+	}
+	absDir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(absRoot, absDir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+
+	if rel == "." {
+		return opt.ModulePath
+	}
+	return opt.ModulePath + "/" + filepath.ToSlash(rel)
+}
+
+// checkPath writes the violations found in filename, or in every .go file
+// below it when it is a directory, and returns ErrCheckFailed if any.
+func checkPath(opt *commandline.Option, filename string, isDir bool) error {
+	failed := false
+	checkFile := func(path string) error {
+		fileOpt, err := opt.ForFile(path)
+		if err != nil {
+			return err
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if ShouldSkip(src, fileOpt) {
+			return nil
+		}
+
+		violations, err := CheckImports(path, src, fileOpt)
+		if err != nil {
+			return err
+		}
+		for _, v := range violations {
+			fmt.Fprintln(os.Stdout, v)
+			failed = true
+		}
+		return nil
+	}
+
+	var err error
+	if isDir {
+		err = WalkGoFiles(opt, filename, checkFile)
+	} else if err = checkFile(filename); err != nil {
+		err = fmt.Errorf("%s: %w", filename, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	if failed {
+		return ErrCheckFailed
+	}
+	return nil
+}
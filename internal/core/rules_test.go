@@ -0,0 +1,81 @@
+package core_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/magicdrive/goreg/internal/commandline"
+	"github.com/magicdrive/goreg/internal/core"
+	"github.com/magicdrive/goreg/internal/model"
+)
+
+func TestCheckImports(t *testing.T) {
+	root := t.TempDir()
+	src := `package core
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/pkg/errors/sub"
+
+	"example.com/m/cmd/goreg"
+	"example.com/m/internal/model"
+)
+`
+
+	rules := model.RulesConfig{
+		Deny:  []string{"github.com/pkg/errors/..."},
+		Hints: map[string]string{"github.com/pkg/errors/...": "use the standard errors package"},
+		Layers: []model.LayerRule{
+			{From: "<local>/internal/...", Deny: []string{"<local>/cmd/..."}, Hint: "cmd depends on internal, not the reverse"},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		filename string
+		expected []string
+	}{
+		{
+			name:     "Denied imports and layers",
+			filename: filepath.Join(root, "internal", "core", "core.go"),
+			expected: []string{
+				filepath.Join(root, "internal", "core", "core.go") + `:6:2: import "github.com/pkg/errors" is denied: use the standard errors package`,
+				filepath.Join(root, "internal", "core", "core.go") + `:7:2: import "github.com/pkg/errors/sub" is denied: use the standard errors package`,
+				filepath.Join(root, "internal", "core", "core.go") + `:9:2: package example.com/m/internal/core may not import "example.com/m/cmd/goreg": cmd depends on internal, not the reverse`,
+			},
+		},
+		{
+			name:     "Layers only apply to matching packages",
+			filename: filepath.Join(root, "cmd", "other", "main.go"),
+			expected: []string{
+				filepath.Join(root, "cmd", "other", "main.go") + `:6:2: import "github.com/pkg/errors" is denied: use the standard errors package`,
+				filepath.Join(root, "cmd", "other", "main.go") + `:7:2: import "github.com/pkg/errors/sub" is denied: use the standard errors package`,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			opt := &commandline.Option{
+				ModulePath: "example.com/m",
+				ModuleRoot: root,
+				Rules:      rules,
+			}
+
+			violations, err := core.CheckImports(tc.filename, []byte(src), opt)
+			if err != nil {
+				t.Fatalf("CheckImports failed: %v", err)
+			}
+			if len(violations) != len(tc.expected) {
+				t.Fatalf("expected %d violations, got %v", len(tc.expected), violations)
+			}
+			for i, v := range violations {
+				if v.String() != tc.expected[i] {
+					t.Errorf("expected %q, got %q", tc.expected[i], v.String())
+				}
+			}
+		})
+	}
+}
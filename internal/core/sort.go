@@ -0,0 +1,127 @@
+package core
+
+import (
+	"strings"
+
+	"github.com/magicdrive/goreg/internal/model"
+)
+
+// lessImport orders imports by strategy, falling back to byte order of the
+// path and then of the alias so that the result is always total.
+func lessImport(a, b *model.ImportPack, strategy model.SortStrategy) bool {
+	if c := compareImports(a, b, strategy); c != 0 {
+		return c < 0
+	}
+	if a.Path != b.Path {
+		return a.Path < b.Path
+	}
+	return a.Alias < b.Alias
+}
+
+func compareImports(a, b *model.ImportPack, strategy model.SortStrategy) int {
+	switch strategy {
+	case model.SortCaseInsensitive:
+		return strings.Compare(strings.ToLower(a.Path), strings.ToLower(b.Path))
+	case model.SortNatural:
+		return compareNatural(a.Path, b.Path)
+	case model.SortSegment:
+		return compareSegments(a.Path, b.Path)
+	case model.SortAlias:
+		return strings.Compare(importName(a), importName(b))
+	default:
+		return strings.Compare(a.Path, b.Path)
+	}
+}
+
+// compareNatural compares a and b treating every run of digits as a number.
+func compareNatural(a, b string) int {
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			na, restA := splitDigits(a)
+			nb, restB := splitDigits(b)
+
+			na = strings.TrimLeft(na, "0")
+			nb = strings.TrimLeft(nb, "0")
+			if len(na) != len(nb) {
+				if len(na) < len(nb) {
+					return -1
+				}
+				return 1
+			}
+			if c := strings.Compare(na, nb); c != 0 {
+				return c
+			}
+			a, b = restA, restB
+			continue
+		}
+
+		if a[0] != b[0] {
+			if a[0] < b[0] {
+				return -1
+			}
+			return 1
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) - len(b)
+}
+
+func splitDigits(s string) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// compareSegments compares the slash-separated elements of a and b in turn.
+func compareSegments(a, b string) int {
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if c := strings.Compare(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	return len(as) - len(bs)
+}
+
+// importName returns the name an import is referred to by in code: its
+// alias, or the last element of its path without a major version suffix.
+// Blank and dot imports have no name and are compared by path.
+func importName(importPack *model.ImportPack) string {
+	switch importPack.Alias {
+	case "":
+		return assumedPackageName(importPack.Path)
+	case "_", ".":
+		return importPack.Path
+	default:
+		return importPack.Alias
+	}
+}
+
+// assumedPackageName guesses the package name of path from its last
+// element, skipping a major version suffix such as /v2.
+func assumedPackageName(path string) string {
+	elems := strings.Split(path, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && isMajorVersion(name) {
+		name = elems[len(elems)-2]
+	}
+	return name
+}
+
+func isMajorVersion(elem string) bool {
+	if len(elem) < 2 || elem[0] != 'v' {
+		return false
+	}
+	for i := 1; i < len(elem); i++ {
+		if !isDigit(elem[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,202 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/magicdrive/goreg/internal/commandline"
+	"github.com/magicdrive/goreg/internal/core"
+	"github.com/magicdrive/goreg/internal/model"
+)
+
+func TestFormatImports_SortStrategy(t *testing.T) {
+	cases := []struct {
+		name      string
+		strategy  model.SortStrategy
+		placement model.AliasPlacementConfig
+		input     string
+		expected  string
+	}{
+		{
+			name:     "Lexical",
+			strategy: model.SortLexical,
+			input: `package main
+
+import (
+	"example.com/api/v2"
+	"example.com/api/v10"
+	"example.com/Zeta"
+	"example.com/alpha"
+)
+`,
+			expected: `package main
+
+import (
+	"example.com/Zeta"
+	"example.com/alpha"
+	"example.com/api/v10"
+	"example.com/api/v2"
+)
+`,
+		},
+		{
+			name:     "Case-insensitive",
+			strategy: model.SortCaseInsensitive,
+			input: `package main
+
+import (
+	"example.com/Zeta"
+	"example.com/beta"
+	"example.com/Alpha"
+)
+`,
+			expected: `package main
+
+import (
+	"example.com/Alpha"
+	"example.com/beta"
+	"example.com/Zeta"
+)
+`,
+		},
+		{
+			name:     "Natural",
+			strategy: model.SortNatural,
+			input: `package main
+
+import (
+	"example.com/api/v10"
+	"example.com/api/v2"
+	"example.com/api/v1"
+	"example.com/api/v02beta"
+)
+`,
+			expected: `package main
+
+import (
+	"example.com/api/v1"
+	"example.com/api/v2"
+	"example.com/api/v02beta"
+	"example.com/api/v10"
+)
+`,
+		},
+		{
+			name:     "Segment",
+			strategy: model.SortSegment,
+			input: `package main
+
+import (
+	"example.com/foo-baz"
+	"example.com/foo/bar"
+	"example.com/foo"
+)
+`,
+			expected: `package main
+
+import (
+	"example.com/foo"
+	"example.com/foo/bar"
+	"example.com/foo-baz"
+)
+`,
+		},
+		{
+			name:     "Alias",
+			strategy: model.SortAlias,
+			input: `package main
+
+import (
+	zlog "example.com/aaa/logging"
+	"example.com/yaml/v3"
+	api "example.com/zzz/api"
+	"example.com/mmm/json"
+)
+`,
+			expected: `package main
+
+import (
+	"example.com/mmm/json"
+	"example.com/yaml/v3"
+
+	api "example.com/zzz/api"
+	zlog "example.com/aaa/logging"
+)
+`,
+		},
+		{
+			name:     "Preserve",
+			strategy: model.SortPreserve,
+			input: `package main
+
+import (
+	_ "example.com/drivers/register"
+	"os"
+	"example.com/drivers"
+	"fmt"
+
+	pkgerrors "github.com/pkg/errors"
+	_ "embed"
+)
+
+import "context"
+`,
+			expected: `package main
+
+import (
+	"os"
+	"fmt"
+	"context"
+
+	_ "embed"
+
+	"example.com/drivers"
+
+	_ "example.com/drivers/register"
+	pkgerrors "github.com/pkg/errors"
+)
+`,
+		},
+		{
+			name:      "Preserve with inline blank imports",
+			strategy:  model.SortPreserve,
+			placement: model.AliasPlacementConfig{Blank: "inline"},
+			input: `package main
+
+import (
+	_ "example.com/drivers/register"
+	"fmt"
+	"example.com/drivers"
+)
+`,
+			expected: `package main
+
+import (
+	"fmt"
+
+	_ "example.com/drivers/register"
+	"example.com/drivers"
+)
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			opt := &commandline.Option{
+				ImportOrder:    model.DefaultOrder,
+				ModulePath:     "myproject/module",
+				SortStrategy:   tc.strategy,
+				AliasPlacement: tc.placement,
+				VerifyFlag:     true,
+			}
+
+			output, err := core.FormatImports([]byte(tc.input), opt)
+			if err != nil {
+				t.Fatalf("FormatImports failed: %v", err)
+			}
+			if string(output) != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, string(output))
+			}
+		})
+	}
+}
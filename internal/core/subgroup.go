@@ -0,0 +1,55 @@
+package core
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/magicdrive/goreg/internal/commandline"
+	"github.com/magicdrive/goreg/internal/model"
+)
+
+// splitThirdParty splits the third-party group into one block per host or
+// per required module. Blocks are ordered by their key, or by first
+// appearance when imports keep their source order.
+func splitThirdParty(imports []*model.ImportPack, opt *commandline.Option) [][]*model.ImportPack {
+	var keys []string
+	blocks := make(map[string][]*model.ImportPack)
+
+	for _, importPack := range imports {
+		key := thirdPartyKey(importPack.Path, opt)
+		if _, ok := blocks[key]; !ok {
+			keys = append(keys, key)
+		}
+		blocks[key] = append(blocks[key], importPack)
+	}
+
+	if opt.SortStrategy != model.SortPreserve {
+		sort.Strings(keys)
+	}
+
+	result := make([][]*model.ImportPack, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, blocks[key])
+	}
+	return result
+}
+
+// thirdPartyKey returns the block path belongs to: its host, or the
+// longest module required in go.mod that contains it. Paths outside every
+// required module fall back to their host.
+func thirdPartyKey(path string, opt *commandline.Option) string {
+	if opt.ThirdPartySubgroup == model.SubgroupModule {
+		var module string
+		for _, require := range opt.Requires {
+			if (path == require || strings.HasPrefix(path, require+"/")) && len(require) > len(module) {
+				module = require
+			}
+		}
+		if module != "" {
+			return module
+		}
+	}
+
+	host, _, _ := strings.Cut(path, "/")
+	return host
+}
@@ -0,0 +1,98 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/magicdrive/goreg/internal/commandline"
+	"github.com/magicdrive/goreg/internal/core"
+	"github.com/magicdrive/goreg/internal/model"
+)
+
+func TestFormatImports_ThirdPartySubgroup(t *testing.T) {
+	input := `package main
+
+import (
+	"k8s.io/client-go/kubernetes"
+	"github.com/spf13/cobra"
+	"fmt"
+	"google.golang.org/grpc"
+	"k8s.io/api/core/v1"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra/doc"
+	"myproject/module/internal"
+)
+`
+
+	cases := []struct {
+		name     string
+		mode     model.SubgroupMode
+		requires []string
+		expected string
+	}{
+		{
+			name: "By host",
+			mode: model.SubgroupHost,
+			expected: `package main
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+
+	"google.golang.org/grpc"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"myproject/module/internal"
+)
+`,
+		},
+		{
+			name:     "By module",
+			mode:     model.SubgroupModule,
+			requires: []string{"github.com/pkg/errors", "github.com/spf13/cobra", "k8s.io/api", "k8s.io/client-go"},
+			expected: `package main
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+
+	"google.golang.org/grpc"
+
+	"k8s.io/api/core/v1"
+
+	"k8s.io/client-go/kubernetes"
+
+	"myproject/module/internal"
+)
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			opt := &commandline.Option{
+				ImportOrder:        model.DefaultOrder,
+				ModulePath:         "myproject/module",
+				ThirdPartySubgroup: tc.mode,
+				Requires:           tc.requires,
+				VerifyFlag:         true,
+			}
+
+			output, err := core.FormatImports([]byte(input), opt)
+			if err != nil {
+				t.Fatalf("FormatImports failed: %v", err)
+			}
+			if string(output) != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, string(output))
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/magicdrive/goreg/internal/commandline"
+)
+
+// ErrVerifyFailed is returned when formatted output would lose or alter
+// something from its input.
+var ErrVerifyFailed = errors.New("verification failed")
+
+// importRegion is the part of a file from its first to its last import
+// declaration, with the specs and comments found in it.
+type importRegion struct {
+	start    int
+	end      int
+	split    bool
+	specs    []string
+	comments []string
+}
+
+// VerifyImports checks that after holds the same multiset of import specs
+// (alias and path) and import comments as before, and that everything
+// outside the import declarations is byte-identical. Comments may only
+// disappear when RemoveImportCommentFlag is set.
+func VerifyImports(before, after []byte, opt *commandline.Option) error {
+	in, err := scanImportRegion(before, opt)
+	if err != nil {
+		return fmt.Errorf("%w: input does not parse: %v", ErrVerifyFailed, err)
+	}
+	out, err := scanImportRegion(after, opt)
+	if err != nil {
+		return fmt.Errorf("%w: output does not parse: %v", ErrVerifyFailed, err)
+	}
+
+	if !bytes.Equal(before[:in.start], after[:out.start]) {
+		return fmt.Errorf("%w: text before the imports changed", ErrVerifyFailed)
+	}
+	// Code sharing a line with the last declaration is moved to a new one.
+	rest := before[in.end:]
+	if in.split {
+		rest = append([]byte("\n"), rest...)
+	}
+	if !bytes.Equal(rest, after[out.end:]) {
+		return fmt.Errorf("%w: text after the imports changed", ErrVerifyFailed)
+	}
+
+	if missing, extra := diffMultiset(in.specs, out.specs); len(missing) > 0 || len(extra) > 0 {
+		return fmt.Errorf("%w: imports changed (missing: %s; unexpected: %s)",
+			ErrVerifyFailed, formatList(missing), formatList(extra))
+	}
+
+	missing, extra := diffMultiset(in.comments, out.comments)
+	if opt.RemoveImportCommentFlag {
+		missing = nil
+	}
+	if len(missing) > 0 || len(extra) > 0 {
+		return fmt.Errorf("%w: import comments changed (missing: %s; unexpected: %s)",
+			ErrVerifyFailed, formatList(missing), formatList(extra))
+	}
+
+	return nil
+}
+
+func scanImportRegion(src []byte, opt *commandline.Option) (*importRegion, error) {
+	fset := token.NewFileSet()
+	node, err := parseImports(fset, src)
+	if err != nil {
+		return nil, err
+	}
+
+	var first, last *ast.GenDecl
+	for _, decl := range node.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+			if first == nil {
+				first = genDecl
+			}
+			last = genDecl
+		}
+	}
+
+	if first == nil {
+		return &importRegion{start: len(src), end: len(src)}, nil
+	}
+
+	// The doc comment belongs to the region: cgo preambles move along with
+	// the `import "C"` they precede.
+	start := first.Pos()
+	if first.Doc != nil {
+		start = first.Doc.Pos()
+	}
+
+	// So does a comment trailing the last declaration: collapsing a block
+	// moves the comment of its only import there.
+	file := fset.File(first.Pos())
+	end := last.End()
+	for _, group := range node.Comments {
+		for _, c := range group.List {
+			if c.Pos() >= end && file.Line(c.Pos()) == file.Line(last.End()) {
+				end = c.End()
+			}
+		}
+	}
+
+	region := &importRegion{start: file.Offset(start)}
+	region.end, region.split = skipSemicolon(src, file.Offset(end))
+
+	for _, imp := range node.Imports {
+		var alias string
+		if imp.Name != nil {
+			alias = imp.Name.Name + " "
+		}
+		region.specs = append(region.specs, alias+imp.Path.Value)
+	}
+
+	for _, group := range node.Comments {
+		for _, c := range group.List {
+			if c.Pos() < start || c.End() > end || isGoregDirective(c.Text) || isGroupHeader(c.Text, opt) {
+				continue
+			}
+			region.comments = append(region.comments, strings.TrimSpace(c.Text))
+		}
+	}
+
+	return region, nil
+}
+
+// diffMultiset returns the elements of want missing from got and the
+// elements of got not in want, counting duplicates.
+func diffMultiset(want, got []string) ([]string, []string) {
+	counts := make(map[string]int)
+	for _, w := range want {
+		counts[w]++
+	}
+	for _, g := range got {
+		counts[g]--
+	}
+
+	var missing, extra []string
+	for k, n := range counts {
+		for ; n > 0; n-- {
+			missing = append(missing, k)
+		}
+		for ; n < 0; n++ {
+			extra = append(extra, k)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(extra)
+	return missing, extra
+}
+
+func formatList(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, ", ")
+}
+
+// sourceToken is a token of a file, without its position.
+type sourceToken struct {
+	tok token.Token
+	lit string
+}
+
+// VerifyAliases checks that after only differs from before in the names of
+// its imports and in the identifiers renamed from a key of renames to its
+// value: the import paths are the same and in the same order, and every
+// other token and comment is unchanged. Blank and dot import names may not
+// change either.
+func VerifyAliases(before, after []byte, renames map[string]string) error {
+	inPaths, in, err := scanAliasTokens(before)
+	if err != nil {
+		return fmt.Errorf("%w: input does not parse: %v", ErrVerifyFailed, err)
+	}
+	outPaths, out, err := scanAliasTokens(after)
+	if err != nil {
+		return fmt.Errorf("%w: output does not parse: %v", ErrVerifyFailed, err)
+	}
+
+	if strings.Join(inPaths, "\n") != strings.Join(outPaths, "\n") {
+		return fmt.Errorf("%w: imports changed (before: %s; after: %s)",
+			ErrVerifyFailed, formatList(inPaths), formatList(outPaths))
+	}
+
+	for i := 0; i < len(in) || i < len(out); i++ {
+		if i >= len(in) || i >= len(out) {
+			return fmt.Errorf("%w: code outside the import names changed", ErrVerifyFailed)
+		}
+		a, b := in[i], out[i]
+		if a == b || (a.tok == token.IDENT && b.tok == token.IDENT && renames[a.lit] == b.lit) {
+			continue
+		}
+		return fmt.Errorf("%w: %s changed to %s", ErrVerifyFailed, tokenText(a), tokenText(b))
+	}
+	return nil
+}
+
+// scanAliasTokens returns the import paths of src in source order and its
+// tokens and comments, leaving out the names of imports other than blank and
+// dot imports.
+func scanAliasTokens(src []byte) ([]string, []sourceToken, error) {
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+	file := fset.File(node.Package)
+
+	var paths []string
+	names := make(map[int]bool)
+	for _, imp := range node.Imports {
+		paths = append(paths, imp.Path.Value)
+		if imp.Name != nil && imp.Name.Name != "_" && imp.Name.Name != "." {
+			names[file.Offset(imp.Name.Pos())] = true
+		}
+	}
+
+	var s scanner.Scanner
+	scanFile := token.NewFileSet().AddFile("", -1, len(src))
+	s.Init(scanFile, src, nil, scanner.ScanComments)
+
+	var tokens []sourceToken
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.IDENT && names[scanFile.Offset(pos)] {
+			continue
+		}
+		tokens = append(tokens, sourceToken{tok: tok, lit: lit})
+	}
+	return paths, tokens, nil
+}
+
+func tokenText(t sourceToken) string {
+	if t.lit != "" {
+		return strconv.Quote(t.lit)
+	}
+	return strconv.Quote(t.tok.String())
+}
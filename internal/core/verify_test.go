@@ -0,0 +1,277 @@
+package core_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/magicdrive/goreg/internal/commandline"
+	"github.com/magicdrive/goreg/internal/core"
+)
+
+func TestVerifyImports(t *testing.T) {
+	before := `// Package main is an example.
+package main
+
+import (
+	"github.com/pkg/errors" // wrap errors
+	// logging
+	mylog "log"
+	"fmt"
+)
+
+func main() {}
+`
+
+	cases := []struct {
+		name    string
+		after   string
+		opt     *commandline.Option
+		wantErr bool
+	}{
+		{
+			name: "Reordered imports",
+			after: `// Package main is an example.
+package main
+
+import (
+	"fmt"
+	// logging
+	mylog "log"
+
+	"github.com/pkg/errors" // wrap errors
+)
+
+func main() {}
+`,
+			opt:     &commandline.Option{},
+			wantErr: false,
+		},
+		{
+			name: "Lost import",
+			after: `// Package main is an example.
+package main
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors" // wrap errors
+	// logging
+)
+
+func main() {}
+`,
+			opt:     &commandline.Option{},
+			wantErr: true,
+		},
+		{
+			name: "Altered alias",
+			after: `// Package main is an example.
+package main
+
+import (
+	"fmt"
+	// logging
+	log "log"
+
+	"github.com/pkg/errors" // wrap errors
+)
+
+func main() {}
+`,
+			opt:     &commandline.Option{},
+			wantErr: true,
+		},
+		{
+			name: "Lost comment",
+			after: `// Package main is an example.
+package main
+
+import (
+	"fmt"
+	mylog "log"
+
+	"github.com/pkg/errors" // wrap errors
+)
+
+func main() {}
+`,
+			opt:     &commandline.Option{},
+			wantErr: true,
+		},
+		{
+			name: "Removed comments with RemoveImportCommentFlag",
+			after: `// Package main is an example.
+package main
+
+import (
+	"fmt"
+	mylog "log"
+
+	"github.com/pkg/errors"
+)
+
+func main() {}
+`,
+			opt:     &commandline.Option{RemoveImportCommentFlag: true},
+			wantErr: false,
+		},
+		{
+			name: "Changed code outside imports",
+			after: `// Package main is an example.
+package main
+
+import (
+	"fmt"
+	// logging
+	mylog "log"
+
+	"github.com/pkg/errors" // wrap errors
+)
+
+func main() { }
+`,
+			opt:     &commandline.Option{},
+			wantErr: true,
+		},
+		{
+			name: "Changed package clause",
+			after: `// Package main is an example!
+package main
+
+import (
+	"fmt"
+	// logging
+	mylog "log"
+
+	"github.com/pkg/errors" // wrap errors
+)
+
+func main() {}
+`,
+			opt:     &commandline.Option{},
+			wantErr: true,
+		},
+		{
+			name:    "Broken output",
+			after:   "package main\n\nimport (\n\t\"fmt\"\n",
+			opt:     &commandline.Option{},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := core.VerifyImports([]byte(before), []byte(tc.after), tc.opt)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error status: %v", err)
+			}
+			if err != nil && !errors.Is(err, core.ErrVerifyFailed) {
+				t.Errorf("expected ErrVerifyFailed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyAliases(t *testing.T) {
+	before := `package main
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// pod is printed.
+var pod v1.Pod
+
+func main() { fmt.Println(pod) }
+`
+
+	cases := []struct {
+		name    string
+		after   string
+		wantErr bool
+	}{
+		{
+			name: "Renamed alias and uses",
+			after: `package main
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// pod is printed.
+var pod corev1.Pod
+
+func main() { fmt.Println(pod) }
+`,
+		},
+		{
+			name: "Identifier renamed without a rename",
+			after: `package main
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// pod is printed.
+var pod corev1.Pod
+
+func main() { fmt.Println(pods) }
+`,
+			wantErr: true,
+		},
+		{
+			name: "Comment changed",
+			after: `package main
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// pod is shown.
+var pod corev1.Pod
+
+func main() { fmt.Println(pod) }
+`,
+			wantErr: true,
+		},
+		{
+			name: "Import dropped",
+			after: `package main
+
+import "fmt"
+
+// pod is printed.
+var pod corev1.Pod
+
+func main() { fmt.Println(pod) }
+`,
+			wantErr: true,
+		},
+		{
+			name:    "Broken output",
+			after:   "package main\n\nimport (\n\t\"fmt\"\n",
+			wantErr: true,
+		},
+	}
+
+	renames := map[string]string{"v1": "corev1"}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := core.VerifyAliases([]byte(before), []byte(tc.after), renames)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error status: %v", err)
+			}
+			if err != nil && !errors.Is(err, core.ErrVerifyFailed) {
+				t.Errorf("expected ErrVerifyFailed, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrFileChanged is returned when a file changed on disk after goreg read it.
+var ErrFileChanged = errors.New("file changed on disk since it was read")
+
+// WriteFileAtomic replaces filename with data. original is the content goreg
+// read from filename; nothing is written when data equals it, and the write
+// is refused with ErrFileChanged when the file no longer holds it. The new
+// content goes to a temporary file in the same directory that is renamed
+// over filename, keeping the original permissions. With a non-empty
+// backupSuffix, the original content is kept in filename+backupSuffix.
+func WriteFileAtomic(filename string, original, data []byte, backupSuffix string) error {
+	if bytes.Equal(original, data) {
+		return nil
+	}
+
+	target, err := filepath.EvalSymlinks(filename)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(target)
+	if err != nil {
+		return err
+	}
+
+	current, err := os.ReadFile(target)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(current, original) {
+		return fmt.Errorf("%s: %w", filename, ErrFileChanged)
+	}
+
+	if backupSuffix != "" {
+		if err := os.WriteFile(filename+backupSuffix, original, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".goreg-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpName, target)
+}
@@ -0,0 +1,104 @@
+package core_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/magicdrive/goreg/internal/core"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	original := []byte("original\n")
+	if err := os.WriteFile(path, original, 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	if err := core.WriteFileAtomic(path, original, []byte("updated\n"), ""); err != nil {
+		t.Fatalf("WriteFileAtomic failed: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	if string(got) != "updated\n" {
+		t.Errorf("expected updated content, got %q", got)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("failed to stat file: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected permissions 0600 to be preserved, got %v", info.Mode().Perm())
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected no temporary files to remain, got %d entries", len(entries))
+	}
+}
+
+func TestWriteFileAtomic_Unchanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	original := []byte("original\n")
+	if err := os.WriteFile(path, original, 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	// The file changed on disk, but identical output must not touch it.
+	if err := os.WriteFile(path, []byte("edited elsewhere\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := core.WriteFileAtomic(path, original, original, ".orig"); err != nil {
+		t.Fatalf("WriteFileAtomic failed: %v", err)
+	}
+
+	if got, _ := os.ReadFile(path); string(got) != "edited elsewhere\n" {
+		t.Errorf("expected file to be left alone, got %q", got)
+	}
+	if _, err := os.Stat(path + ".orig"); !os.IsNotExist(err) {
+		t.Errorf("expected no backup for unchanged content, got %v", err)
+	}
+}
+
+func TestWriteFileAtomic_ChangedOnDisk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte("edited elsewhere\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	err := core.WriteFileAtomic(path, []byte("original\n"), []byte("updated\n"), "")
+	if !errors.Is(err, core.ErrFileChanged) {
+		t.Fatalf("expected ErrFileChanged, got %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "edited elsewhere\n" {
+		t.Errorf("expected file to be left alone, got %q", got)
+	}
+}
+
+func TestWriteFileAtomic_Backup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	original := []byte("original\n")
+	if err := os.WriteFile(path, original, 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	if err := core.WriteFileAtomic(path, original, []byte("updated\n"), ".bak"); err != nil {
+		t.Fatalf("WriteFileAtomic failed: %v", err)
+	}
+
+	if got, _ := os.ReadFile(path + ".bak"); string(got) != "original\n" {
+		t.Errorf("expected backup to hold the original content, got %q", got)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "updated\n" {
+		t.Errorf("expected updated content, got %q", got)
+	}
+}
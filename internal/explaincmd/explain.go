@@ -0,0 +1,57 @@
+package explaincmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/magicdrive/goreg/internal/commandline"
+	"github.com/magicdrive/goreg/internal/core"
+	"github.com/magicdrive/goreg/internal/model"
+)
+
+func Execute(opt *commandline.Option, w io.Writer) error {
+	opt, err := opt.ForFile(opt.FileName)
+	if err != nil {
+		return err
+	}
+
+	src, err := os.ReadFile(opt.FileName)
+	if err != nil {
+		return err
+	}
+
+	explanations, err := core.ExplainImports(src, opt)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LINE\tPATH\tALIAS\tGROUP\tRULE\tPOSITION")
+	for _, e := range explanations {
+		alias := e.Alias
+		if alias == "" {
+			alias = "-"
+		}
+		position := "-"
+		if e.Position > 0 {
+			position = strconv.Itoa(e.Position)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			e.Line, e.Path, alias, e.Group, describeRule(e.Rule, opt), position)
+	}
+	return tw.Flush()
+}
+
+func describeRule(rule model.ImportRule, opt *commandline.Option) string {
+	switch rule {
+	case model.RuleLocalModule:
+		return fmt.Sprintf("%s %q", rule, opt.ModulePath)
+	case model.RuleOrganization:
+		return fmt.Sprintf("%s %q", rule, opt.OrganizationName)
+	default:
+		return rule.String()
+	}
+}
@@ -0,0 +1,74 @@
+package explaincmd_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/magicdrive/goreg/internal/commandline"
+	"github.com/magicdrive/goreg/internal/explaincmd"
+	"github.com/magicdrive/goreg/internal/model"
+)
+
+func TestExecute(t *testing.T) {
+	src := `package main
+
+import (
+	"myproject/module/utils"
+	errlib "github.com/pkg/errors"
+	"fmt"
+	"github.com/myorg/lib"
+)
+`
+	filename := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(filename, []byte(src), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	opt := &commandline.Option{
+		ImportOrder:      model.DefaultOrder,
+		OrganizationName: "github.com/myorg",
+		ModulePath:       "myproject/module",
+		FileName:         filename,
+	}
+
+	var buf bytes.Buffer
+	if err := explaincmd.Execute(opt, &buf); err != nil {
+		t.Fatalf("Execute() returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected header and 4 rows, got %d lines:\n%s", len(lines), buf.String())
+	}
+
+	expected := [][]string{
+		{"LINE", "PATH", "ALIAS", "GROUP", "RULE", "POSITION"},
+		{"4", "myproject/module/utils", "-", "local", "local module prefix", "4"},
+		{"5", "github.com/pkg/errors", "errlib", "thirdparty", "fallback third-party", "2"},
+		{"6", "fmt", "-", "std", "stdlib (no dot in path)", "1"},
+		{"7", "github.com/myorg/lib", "-", "organization", "organization prefix", "3"},
+	}
+	for i, want := range expected {
+		for _, field := range want {
+			if !strings.Contains(lines[i], field) {
+				t.Errorf("line %d: expected %q in %q", i, field, lines[i])
+			}
+		}
+	}
+}
+
+func TestExecute_FileNotFound(t *testing.T) {
+	opt := &commandline.Option{
+		ImportOrder: model.DefaultOrder,
+		ModulePath:  "myproject/module",
+		FileName:    filepath.Join(t.TempDir(), "missing.go"),
+	}
+
+	var buf bytes.Buffer
+	if err := explaincmd.Execute(opt, &buf); err == nil {
+		t.Fatal("expected error for missing file, but got nil")
+	}
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+)
+
+// validateAliases reports [aliases] entries that cannot be written as the
+// name of an import spec.
+func validateAliases(aliases map[string]string) []error {
+	var errs []error
+	for path, alias := range aliases {
+		key := []string{"aliases", path}
+		if path == "" {
+			errs = append(errs, &FieldError{Key: key, Err: errors.New("empty import path")})
+		}
+		if !token.IsIdentifier(alias) || alias == "_" {
+			errs = append(errs, &FieldError{Key: key, Err: fmt.Errorf("invalid alias %q", alias)})
+		}
+	}
+	return errs
+}
@@ -1,8 +1,27 @@
 package model
 
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
 type Config struct {
-	Import ImportConfig `toml:"import"`
-	Format FormatConfig `toml:"format"`
+	Exclude          []string `toml:"exclude"`
+	NoGitignore      bool     `toml:"no_gitignore"`
+	IncludeGenerated bool     `toml:"include_generated"`
+	NoCache          bool     `toml:"no_cache"`
+
+	Import    ImportConfig      `toml:"import"`
+	Format    FormatConfig      `toml:"format"`
+	Aliases   map[string]string `toml:"aliases"`
+	Rules     RulesConfig       `toml:"rules"`
+	Overrides []OverrideConfig  `toml:"overrides"`
+
+	// FilePath is the goreg.toml the config was loaded from, if any.
+	FilePath string `toml:"-"`
 }
 
 type ImportConfig struct {
@@ -12,9 +31,62 @@ type ImportConfig struct {
 }
 
 type FormatConfig struct {
-	MinimizeGroup       bool `toml:"minimize_group"`
-	SortIncludeAlias    bool `toml:"sort_include_alias"`
-	RemoveImportComment bool `toml:"remove_import_comment"`
+	MinimizeGroup        bool                 `toml:"minimize_group"`
+	SortIncludeAlias     bool                 `toml:"sort_include_alias"`
+	RemoveImportComment  bool                 `toml:"remove_import_comment"`
+	RemoveRedundantAlias bool                 `toml:"remove_redundant_alias"`
+	Gofmt                bool                 `toml:"gofmt"`
+	Sort                 string               `toml:"sort"`
+	Pinned               []string             `toml:"pinned"`
+	PinnedBottom         []string             `toml:"pinned_bottom"`
+	ThirdPartySubgroup   string               `toml:"thirdparty_subgroup"`
+	GroupSeparator       string               `toml:"group_separator"`
+	SingleImport         string               `toml:"single_import"`
+	EmitGroupHeaders     bool                 `toml:"emit_group_headers"`
+	GroupHeaders         GroupHeadersConfig   `toml:"group_headers"`
+	AliasPlacement       AliasPlacementConfig `toml:"alias_placement"`
+}
+
+// OverrideConfig changes settings for the files matching one of Files.
+// Keys left unset keep the value of the surrounding configuration.
+type OverrideConfig struct {
+	Files  []string             `toml:"files"`
+	Import ImportOverrideConfig `toml:"import"`
+	Format FormatOverrideConfig `toml:"format"`
+}
+
+type ImportOverrideConfig struct {
+	OrganizationModule *string `toml:"organization_module"`
+	Order              *string `toml:"order"`
+}
+
+type FormatOverrideConfig struct {
+	MinimizeGroup        *bool                `toml:"minimize_group"`
+	SortIncludeAlias     *bool                `toml:"sort_include_alias"`
+	RemoveImportComment  *bool                `toml:"remove_import_comment"`
+	RemoveRedundantAlias *bool                `toml:"remove_redundant_alias"`
+	Sort                 *string              `toml:"sort"`
+	Pinned               *[]string            `toml:"pinned"`
+	PinnedBottom         *[]string            `toml:"pinned_bottom"`
+	ThirdPartySubgroup   *string              `toml:"thirdparty_subgroup"`
+	GroupSeparator       *string              `toml:"group_separator"`
+	SingleImport         *string              `toml:"single_import"`
+	EmitGroupHeaders     *bool                `toml:"emit_group_headers"`
+	AliasPlacement       AliasPlacementConfig `toml:"alias_placement"`
+}
+
+// FieldError reports an invalid value for the goreg.toml key at Key.
+type FieldError struct {
+	Key []string
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", strings.Join(e.Key, "."), e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
 }
 
 func (c *Config) SetDefaults() {
@@ -22,3 +94,100 @@ func (c *Config) SetDefaults() {
 		c.Import.Order = "std,thirdparty,organization,local"
 	}
 }
+
+// Validate checks values that decode fine but are not meaningful to goreg.
+// Every returned error is a *FieldError.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.Import.Order != "" {
+		if _, err := GenerateOrderStrings(c.Import.Order); err != nil {
+			errs = append(errs, &FieldError{Key: []string{"import", "order"}, Err: err})
+		}
+	}
+
+	if _, err := ParseSortStrategy(c.Format.Sort); err != nil {
+		errs = append(errs, &FieldError{Key: []string{"format", "sort"}, Err: err})
+	}
+	if _, err := ParseSubgroupMode(c.Format.ThirdPartySubgroup); err != nil {
+		errs = append(errs, &FieldError{Key: []string{"format", "thirdparty_subgroup"}, Err: err})
+	}
+	if _, err := ParseGroupSeparator(c.Format.GroupSeparator); err != nil {
+		errs = append(errs, &FieldError{Key: []string{"format", "group_separator"}, Err: err})
+	}
+	if _, err := ParseSingleImport(c.Format.SingleImport); err != nil {
+		errs = append(errs, &FieldError{Key: []string{"format", "single_import"}, Err: err})
+	}
+	errs = append(errs, validatePins([]string{"format", "pinned"}, c.Format.Pinned)...)
+	errs = append(errs, validatePins([]string{"format", "pinned_bottom"}, c.Format.PinnedBottom)...)
+	errs = append(errs, validateAliases(c.Aliases)...)
+	errs = append(errs, c.Rules.Validate([]string{"rules"})...)
+	errs = append(errs, c.Format.GroupHeaders.Validate([]string{"format", "group_headers"})...)
+	errs = append(errs, c.Format.AliasPlacement.Validate([]string{"format", "alias_placement"})...)
+
+	for _, pattern := range c.Exclude {
+		if err := ValidateGlob(pattern); err != nil {
+			errs = append(errs, &FieldError{Key: []string{"exclude"}, Err: err})
+		}
+	}
+
+	for i, override := range c.Overrides {
+		key := []string{"overrides", strconv.Itoa(i)}
+
+		if len(override.Files) == 0 {
+			errs = append(errs, &FieldError{Key: append(key, "files"), Err: errors.New("at least one pattern is required")})
+		}
+		for _, pattern := range override.Files {
+			if err := ValidateGlob(pattern); err != nil {
+				errs = append(errs, &FieldError{Key: append(key, "files"), Err: err})
+			}
+		}
+		if override.Import.Order != nil {
+			if _, err := GenerateOrderStrings(*override.Import.Order); err != nil {
+				errs = append(errs, &FieldError{Key: append(key, "import", "order"), Err: err})
+			}
+		}
+		if override.Format.Sort != nil {
+			if _, err := ParseSortStrategy(*override.Format.Sort); err != nil {
+				errs = append(errs, &FieldError{Key: append(key, "format", "sort"), Err: err})
+			}
+		}
+		if override.Format.ThirdPartySubgroup != nil {
+			if _, err := ParseSubgroupMode(*override.Format.ThirdPartySubgroup); err != nil {
+				errs = append(errs, &FieldError{Key: append(key, "format", "thirdparty_subgroup"), Err: err})
+			}
+		}
+		if override.Format.GroupSeparator != nil {
+			if _, err := ParseGroupSeparator(*override.Format.GroupSeparator); err != nil {
+				errs = append(errs, &FieldError{Key: append(key, "format", "group_separator"), Err: err})
+			}
+		}
+		if override.Format.SingleImport != nil {
+			if _, err := ParseSingleImport(*override.Format.SingleImport); err != nil {
+				errs = append(errs, &FieldError{Key: append(key, "format", "single_import"), Err: err})
+			}
+		}
+		if override.Format.Pinned != nil {
+			errs = append(errs, validatePins(append(key, "format", "pinned"), *override.Format.Pinned)...)
+		}
+		if override.Format.PinnedBottom != nil {
+			errs = append(errs, validatePins(append(key, "format", "pinned_bottom"), *override.Format.PinnedBottom)...)
+		}
+		errs = append(errs, override.Format.AliasPlacement.Validate(append(key, "format", "alias_placement"))...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidateGlob reports a malformed segment of a slash-separated glob pattern.
+func ValidateGlob(pattern string) error {
+	if pattern == "" {
+		return errors.New("empty pattern")
+	}
+	for _, segment := range strings.Split(pattern, "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"errors"
+	"strings"
+)
+
+// defaultGroupHeaders are the header comments written when
+// [format.group_headers] leaves a group unset.
+var defaultGroupHeaders = map[ImportGroup]string{
+	StdLib:       "Standard library",
+	ThirdParty:   "Third-party",
+	Organization: "Organization",
+	Local:        "Local",
+	Blank:        "Blank imports",
+	Dot:          "Dot imports",
+	Alias:        "Aliased imports",
+}
+
+// GroupHeadersConfig is the [format.group_headers] table.
+type GroupHeadersConfig struct {
+	Std          string `toml:"std"`
+	ThirdParty   string `toml:"thirdparty"`
+	Organization string `toml:"organization"`
+	Local        string `toml:"local"`
+	Blank        string `toml:"blank"`
+	Dot          string `toml:"dot"`
+	Alias        string `toml:"alias"`
+}
+
+// Text returns the header of group without the leading "//".
+func (c GroupHeadersConfig) Text(group ImportGroup) string {
+	var text string
+	switch group {
+	case StdLib:
+		text = c.Std
+	case ThirdParty:
+		text = c.ThirdParty
+	case Organization:
+		text = c.Organization
+	case Local:
+		text = c.Local
+	case Blank:
+		text = c.Blank
+	case Dot:
+		text = c.Dot
+	case Alias:
+		text = c.Alias
+	}
+
+	if text = normalizeHeader(text); text == "" {
+		return defaultGroupHeaders[group]
+	}
+	return text
+}
+
+// IsHeader reports whether comment is a header goreg writes, configured or
+// default, so that stale headers can be recognized and replaced.
+func (c GroupHeadersConfig) IsHeader(comment string) bool {
+	text := normalizeHeader(comment)
+	if text == "" {
+		return false
+	}
+	for group, header := range defaultGroupHeaders {
+		if text == header || text == c.Text(group) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate reports header texts that do not fit on a single comment line.
+// key is the path of the table in goreg.toml.
+func (c GroupHeadersConfig) Validate(key []string) []error {
+	var errs []error
+	for name, text := range map[string]string{
+		"std":          c.Std,
+		"thirdparty":   c.ThirdParty,
+		"organization": c.Organization,
+		"local":        c.Local,
+		"blank":        c.Blank,
+		"dot":          c.Dot,
+		"alias":        c.Alias,
+	} {
+		if strings.ContainsAny(text, "\r\n") || strings.Contains(text, "*/") {
+			errs = append(errs, &FieldError{
+				Key: append(append([]string{}, key...), name),
+				Err: errors.New("header must be a single line"),
+			})
+		}
+	}
+	return errs
+}
+
+func normalizeHeader(s string) string {
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s), "//"))
+}
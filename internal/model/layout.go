@@ -0,0 +1,79 @@
+package model
+
+import "fmt"
+
+// GroupSeparator decides what goes between two import groups.
+type GroupSeparator int
+
+const (
+	// SeparatorBlank puts one blank line between groups.
+	SeparatorBlank GroupSeparator = iota
+	// SeparatorNone writes the groups back to back.
+	SeparatorNone
+)
+
+var groupSeparatorWords = map[string]GroupSeparator{
+	"blank": SeparatorBlank,
+	"none":  SeparatorNone,
+}
+
+func (s GroupSeparator) String() string {
+	for word, separator := range groupSeparatorWords {
+		if separator == s {
+			return word
+		}
+	}
+	return "unknown"
+}
+
+// ParseGroupSeparator parses a group_separator value. An empty value is
+// SeparatorBlank.
+func ParseGroupSeparator(s string) (GroupSeparator, error) {
+	if s == "" {
+		return SeparatorBlank, nil
+	}
+	if separator, ok := groupSeparatorWords[s]; ok {
+		return separator, nil
+	}
+	return 0, fmt.Errorf("invalid group separator: %s (want blank or none)", s)
+}
+
+// SingleImport decides between `import "fmt"` and `import ( ... )`.
+type SingleImport int
+
+const (
+	// SinglePreserve keeps single-line imports as they are and writes
+	// parenthesized declarations as a block.
+	SinglePreserve SingleImport = iota
+	// SingleCollapse writes a block holding one import as `import "fmt"`.
+	SingleCollapse
+	// SingleExpand merges single-line imports into a parenthesized block.
+	SingleExpand
+)
+
+var singleImportWords = map[string]SingleImport{
+	"preserve": SinglePreserve,
+	"collapse": SingleCollapse,
+	"expand":   SingleExpand,
+}
+
+func (s SingleImport) String() string {
+	for word, mode := range singleImportWords {
+		if mode == s {
+			return word
+		}
+	}
+	return "unknown"
+}
+
+// ParseSingleImport parses a single_import value. An empty value is
+// SinglePreserve.
+func ParseSingleImport(s string) (SingleImport, error) {
+	if s == "" {
+		return SinglePreserve, nil
+	}
+	if mode, ok := singleImportWords[s]; ok {
+		return mode, nil
+	}
+	return 0, fmt.Errorf("invalid single import mode: %s (want preserve, collapse or expand)", s)
+}
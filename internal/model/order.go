@@ -0,0 +1,102 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var wordMap = map[string]ImportGroup{
+	"std":          StdLib,
+	"stdlib":       StdLib,
+	"s":            StdLib,
+	"thirdparty":   ThirdParty,
+	"third_party":  ThirdParty,
+	"3rdparty":     ThirdParty,
+	"3rd_party":    ThirdParty,
+	"3rd":          ThirdParty,
+	"3":            ThirdParty,
+	"t":            ThirdParty,
+	"local":        Local,
+	"l":            Local,
+	"organization": Organization,
+	"org":          Organization,
+	"o":            Organization,
+	"blank":        Blank,
+	"dot":          Dot,
+	"alias":        Alias,
+}
+
+func FilterValidWords(input string) ([]ImportGroup, error) {
+	result := make([]ImportGroup, 0, 16)
+	var sb strings.Builder
+
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		if c == ',' {
+			word := sb.String()
+			if id, exists := wordMap[word]; !exists {
+				return nil, fmt.Errorf("invalid import group: %s", word)
+			} else {
+				result = append(result, id)
+			}
+			sb.Reset()
+		} else if c != ' ' {
+			sb.WriteByte(c)
+		}
+	}
+
+	if sb.Len() > 0 {
+		word := sb.String()
+		if id, exists := wordMap[word]; !exists {
+			return nil, fmt.Errorf("invalid import group: %s", word)
+		} else {
+			result = append(result, id)
+		}
+	}
+
+	return result, nil
+}
+
+func GenerateOrderStrings(input string) ([]ImportGroup, error) {
+	validOrder, err := FilterValidWords(input)
+	if err != nil {
+		return nil, err
+	}
+	result := Unique(validOrder)
+
+	for _, group := range baseGroups {
+		if !slices.Contains(result, group) {
+			return nil, fmt.Errorf("order must include all of std, thirdparty, organization, and local")
+		}
+	}
+	return result, nil
+}
+
+// WithOwnGroups returns order extended by the dedicated groups of the kinds
+// placed in their own group that order does not mention yet, in the order
+// blank, dot, alias.
+func WithOwnGroups(order []ImportGroup, placement AliasPlacementConfig, sortIncludeAlias bool) []ImportGroup {
+	result := order
+	for _, kind := range []ImportKind{KindBlank, KindDot, KindAlias} {
+		group := OwnGroupOf(kind)
+		if placement.Placement(kind, sortIncludeAlias) == PlaceOwnGroup && !slices.Contains(result, group) {
+			result = append(append([]ImportGroup{}, result...), group)
+		}
+	}
+	return result
+}
+
+func Unique[T comparable](arr []T) []T {
+	seen := make(map[T]struct{}, len(arr))
+	result := make([]T, 0, len(arr))
+
+	for _, v := range arr {
+		if _, exists := seen[v]; !exists {
+			seen[v] = struct{}{}
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
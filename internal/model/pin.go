@@ -0,0 +1,84 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// Pin anchors an import at the top or bottom of its group.
+type Pin int
+
+const (
+	PinNone Pin = iota
+	PinTop
+	PinBottom
+)
+
+// PinEntry is one entry of pinned or pinned_bottom: a path, optionally
+// preceded by the alias the import must have, like `_ "embed"`.
+type PinEntry struct {
+	Alias    string
+	Path     string
+	HasAlias bool
+}
+
+// ParsePinEntry parses entries such as `context`, `"context"` and
+// `_ "embed"`.
+func ParsePinEntry(s string) (PinEntry, error) {
+	var entry PinEntry
+
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 1:
+		entry.Path = fields[0]
+	case 2:
+		entry.Alias, entry.Path, entry.HasAlias = fields[0], fields[1], true
+		if entry.Alias != "_" && entry.Alias != "." && !token.IsIdentifier(entry.Alias) {
+			return entry, fmt.Errorf("invalid pinned import %q: bad alias %s", s, entry.Alias)
+		}
+	default:
+		return entry, fmt.Errorf("invalid pinned import %q: want path or alias \"path\"", s)
+	}
+
+	if unquoted, err := strconv.Unquote(entry.Path); err == nil {
+		entry.Path = unquoted
+	}
+	if entry.Path == "" {
+		return entry, errors.New("invalid pinned import: empty path")
+	}
+	return entry, nil
+}
+
+// Matches reports whether an import of path with alias is this entry.
+func (e PinEntry) Matches(alias, path string) bool {
+	return e.Path == path && (!e.HasAlias || e.Alias == alias)
+}
+
+// FindPin returns where the import of path with alias is pinned and its
+// index in the list that pins it. Invalid entries never match.
+func FindPin(alias, path string, top, bottom []string) (Pin, int) {
+	for i, s := range top {
+		if entry, err := ParsePinEntry(s); err == nil && entry.Matches(alias, path) {
+			return PinTop, i
+		}
+	}
+	for i, s := range bottom {
+		if entry, err := ParsePinEntry(s); err == nil && entry.Matches(alias, path) {
+			return PinBottom, i
+		}
+	}
+	return PinNone, 0
+}
+
+func validatePins(key []string, entries []string) []error {
+	var errs []error
+	for _, s := range entries {
+		if _, err := ParsePinEntry(s); err != nil {
+			errs = append(errs, &FieldError{Key: key, Err: err})
+		}
+	}
+	return errs
+}
@@ -0,0 +1,166 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ImportKind tells plain imports apart from the three kinds of named import.
+type ImportKind int
+
+const (
+	KindPlain ImportKind = iota
+	KindBlank
+	KindDot
+	KindAlias
+)
+
+// KindOf returns the kind of an import with the given alias.
+func KindOf(alias string) ImportKind {
+	switch alias {
+	case "":
+		return KindPlain
+	case "_":
+		return KindBlank
+	case ".":
+		return KindDot
+	default:
+		return KindAlias
+	}
+}
+
+// Placement decides where imports of one kind go within their group.
+type Placement int
+
+const (
+	// PlaceSubgroupEnd puts them in a blank-line-separated block at the end
+	// of their group.
+	PlaceSubgroupEnd Placement = iota
+	// PlaceInline sorts them together with the plain imports.
+	PlaceInline
+	// PlaceSubgroupStart puts them in a blank-line-separated block at the
+	// start of their group.
+	PlaceSubgroupStart
+	// PlaceOwnGroup moves them into a group of their own, placed by order.
+	PlaceOwnGroup
+)
+
+var placementWords = map[string]Placement{
+	"subgroup-end":   PlaceSubgroupEnd,
+	"inline":         PlaceInline,
+	"subgroup-start": PlaceSubgroupStart,
+	"own-group":      PlaceOwnGroup,
+}
+
+func (p Placement) String() string {
+	for word, placement := range placementWords {
+		if placement == p {
+			return word
+		}
+	}
+	return "unknown"
+}
+
+func ParsePlacement(s string) (Placement, error) {
+	if p, ok := placementWords[s]; ok {
+		return p, nil
+	}
+	return 0, fmt.Errorf("invalid alias placement: %s (want inline, subgroup-end, subgroup-start or own-group)", s)
+}
+
+// AliasPlacementConfig is the [format.alias_placement] table. Empty values
+// fall back to the placement implied by sort_include_alias.
+type AliasPlacementConfig struct {
+	Blank string `toml:"blank"`
+	Dot   string `toml:"dot"`
+	Alias string `toml:"alias"`
+}
+
+// Merge returns c with every non-empty value of other applied.
+func (c AliasPlacementConfig) Merge(other AliasPlacementConfig) AliasPlacementConfig {
+	if other.Blank != "" {
+		c.Blank = other.Blank
+	}
+	if other.Dot != "" {
+		c.Dot = other.Dot
+	}
+	if other.Alias != "" {
+		c.Alias = other.Alias
+	}
+	return c
+}
+
+// Validate returns a *FieldError, keyed below key, for every invalid value.
+func (c AliasPlacementConfig) Validate(key []string) []error {
+	var errs []error
+	for _, field := range []struct {
+		name  string
+		value string
+	}{{"blank", c.Blank}, {"dot", c.Dot}, {"alias", c.Alias}} {
+		if field.value == "" {
+			continue
+		}
+		if _, err := ParsePlacement(field.value); err != nil {
+			errs = append(errs, &FieldError{Key: append(append([]string{}, key...), field.name), Err: err})
+		}
+	}
+	return errs
+}
+
+// Placement returns the placement of imports of kind. Kinds without an
+// explicit value keep goreg's historical behavior: inline when
+// sortIncludeAlias is set, a block at the end of the group otherwise.
+func (c AliasPlacementConfig) Placement(kind ImportKind, sortIncludeAlias bool) Placement {
+	var value string
+	switch kind {
+	case KindPlain:
+		return PlaceInline
+	case KindBlank:
+		value = c.Blank
+	case KindDot:
+		value = c.Dot
+	case KindAlias:
+		value = c.Alias
+	}
+
+	if p, err := ParsePlacement(value); err == nil {
+		return p
+	}
+	if sortIncludeAlias {
+		return PlaceInline
+	}
+	return PlaceSubgroupEnd
+}
+
+// ParseAliasPlacement parses the --alias-placement flag value, a
+// comma-separated list of kind=placement pairs such as
+// "blank=own-group,alias=inline".
+func ParseAliasPlacement(s string) (AliasPlacementConfig, error) {
+	var c AliasPlacementConfig
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		kind, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return c, fmt.Errorf("invalid alias placement %q: want kind=placement", pair)
+		}
+		if _, err := ParsePlacement(value); err != nil {
+			return c, err
+		}
+
+		switch kind {
+		case "blank":
+			c.Blank = value
+		case "dot":
+			c.Dot = value
+		case "alias":
+			c.Alias = value
+		default:
+			return c, fmt.Errorf("invalid import kind: %s (want blank, dot or alias)", kind)
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LocalPlaceholder stands for the local module path in [rules] patterns.
+const LocalPlaceholder = "<local>"
+
+// RulesConfig is the [rules] table checked by --check.
+type RulesConfig struct {
+	Deny   []string          `toml:"deny"`
+	Hints  map[string]string `toml:"hints"`
+	Layers []LayerRule       `toml:"layers"`
+}
+
+// LayerRule forbids the packages matching From to import those matching
+// one of Deny.
+type LayerRule struct {
+	From string   `toml:"from"`
+	Deny []string `toml:"deny"`
+	Hint string   `toml:"hint"`
+}
+
+// MatchImportPattern reports whether the import path matches pattern: an
+// exact path, or a path ending in "/..." matching it and every path below.
+// LocalPlaceholder is replaced by modulePath.
+func MatchImportPattern(pattern, path, modulePath string) bool {
+	pattern = strings.ReplaceAll(pattern, LocalPlaceholder, modulePath)
+	if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+		return path == prefix || strings.HasPrefix(path, prefix+"/")
+	}
+	return path == pattern
+}
+
+// Validate reports empty patterns and layers without a package to apply to
+// or imports to deny. key is the path of the table in goreg.toml.
+func (c RulesConfig) Validate(key []string) []error {
+	var errs []error
+	sub := func(parts ...string) []string {
+		return append(append([]string{}, key...), parts...)
+	}
+
+	for _, pattern := range c.Deny {
+		if err := validateImportPattern(pattern); err != nil {
+			errs = append(errs, &FieldError{Key: sub("deny"), Err: err})
+		}
+	}
+	for i, layer := range c.Layers {
+		index := strconv.Itoa(i)
+		if err := validateImportPattern(layer.From); err != nil {
+			errs = append(errs, &FieldError{Key: sub("layers", index, "from"), Err: err})
+		}
+		if len(layer.Deny) == 0 {
+			errs = append(errs, &FieldError{Key: sub("layers", index, "deny"), Err: errors.New("at least one pattern is required")})
+		}
+		for _, pattern := range layer.Deny {
+			if err := validateImportPattern(pattern); err != nil {
+				errs = append(errs, &FieldError{Key: sub("layers", index, "deny"), Err: err})
+			}
+		}
+	}
+	return errs
+}
+
+func validateImportPattern(pattern string) error {
+	if pattern == "" {
+		return errors.New("empty pattern")
+	}
+	if strings.Contains(strings.TrimSuffix(pattern, "/..."), "...") {
+		return fmt.Errorf("invalid pattern %q: \"...\" is only allowed as a trailing \"/...\"", pattern)
+	}
+	return nil
+}
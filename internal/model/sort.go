@@ -0,0 +1,53 @@
+package model
+
+import "fmt"
+
+// SortStrategy decides how imports are ordered within a group.
+type SortStrategy int
+
+const (
+	// SortLexical compares import paths byte by byte.
+	SortLexical SortStrategy = iota
+	// SortCaseInsensitive compares import paths ignoring case.
+	SortCaseInsensitive
+	// SortNatural compares runs of digits by their numeric value, so v2
+	// sorts before v10.
+	SortNatural
+	// SortSegment compares import paths element by element, so foo/bar
+	// sorts before foo-baz.
+	SortSegment
+	// SortAlias compares the names imports are referred to by: their alias,
+	// or the last element of their path.
+	SortAlias
+	// SortPreserve keeps the relative order imports have in the source.
+	SortPreserve
+)
+
+var sortStrategyWords = map[string]SortStrategy{
+	"lexical":          SortLexical,
+	"case-insensitive": SortCaseInsensitive,
+	"natural":          SortNatural,
+	"segment":          SortSegment,
+	"alias":            SortAlias,
+	"preserve":         SortPreserve,
+}
+
+func (s SortStrategy) String() string {
+	for word, strategy := range sortStrategyWords {
+		if strategy == s {
+			return word
+		}
+	}
+	return "unknown"
+}
+
+// ParseSortStrategy parses a sort value. An empty value is SortLexical.
+func ParseSortStrategy(s string) (SortStrategy, error) {
+	if s == "" {
+		return SortLexical, nil
+	}
+	if strategy, ok := sortStrategyWords[s]; ok {
+		return strategy, nil
+	}
+	return 0, fmt.Errorf("invalid sort: %s (want lexical, case-insensitive, natural, segment, alias or preserve)", s)
+}
@@ -9,14 +9,107 @@ const (
 	ThirdParty
 	Organization
 	Local
+	// Cgo is the pseudo-package "C". It never joins a group and cannot be
+	// used in an order.
+	Cgo
+	// Blank, Dot and Alias hold the imports of that kind whose placement is
+	// own-group. They may appear in an order but are not required.
+	Blank
+	Dot
+	Alias
 )
 
+// baseGroups are the groups every order must include.
+var baseGroups = []ImportGroup{StdLib, ThirdParty, Organization, Local}
+
+// OwnGroupOf returns the dedicated group for imports of kind.
+func OwnGroupOf(kind ImportKind) ImportGroup {
+	switch kind {
+	case KindBlank:
+		return Blank
+	case KindDot:
+		return Dot
+	default:
+		return Alias
+	}
+}
+
+func (g ImportGroup) String() string {
+	switch g {
+	case StdLib:
+		return "std"
+	case ThirdParty:
+		return "thirdparty"
+	case Organization:
+		return "organization"
+	case Local:
+		return "local"
+	case Cgo:
+		return "cgo"
+	case Blank:
+		return "blank"
+	case Dot:
+		return "dot"
+	case Alias:
+		return "alias"
+	default:
+		return "unknown"
+	}
+}
+
+// ImportRule is the classification rule that decided an ImportGroup.
+type ImportRule int
+
+const (
+	RuleLocalModule ImportRule = iota
+	RuleOrganization
+	RuleStdLib
+	RuleThirdParty
+	RuleCgo
+	RuleOwnGroup
+)
+
+func (r ImportRule) String() string {
+	switch r {
+	case RuleLocalModule:
+		return "local module prefix"
+	case RuleOrganization:
+		return "organization prefix"
+	case RuleStdLib:
+		return "stdlib (no dot in path)"
+	case RuleThirdParty:
+		return "fallback third-party"
+	case RuleCgo:
+		return "cgo (kept with its preamble)"
+	case RuleOwnGroup:
+		return "alias_placement own-group"
+	default:
+		return "unknown"
+	}
+}
+
 type ImportPack struct {
 	Entity      *ast.ImportSpec
 	LineComment *ast.Comment
 	Doc         []string
 	End         string
 	Alias       string
+	Path        string
+	Placement   Placement
+	Pin         Pin
+	PinIndex    int
+	Group       ImportGroup
+}
+
+// ImportExplanation describes why an import spec landed where it did.
+// Position is 0 for imports that stay outside the rewritten block.
+type ImportExplanation struct {
+	Path     string
+	Alias    string
+	Line     int
+	Group    ImportGroup
+	Rule     ImportRule
+	Position int
 }
 
 const DefaultOrderString = "std,thirdparty,organization,local"
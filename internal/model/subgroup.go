@@ -0,0 +1,43 @@
+package model
+
+import "fmt"
+
+// SubgroupMode decides how the third-party group is split into
+// blank-line-separated blocks.
+type SubgroupMode int
+
+const (
+	// SubgroupNone keeps the third-party group in one block.
+	SubgroupNone SubgroupMode = iota
+	// SubgroupHost makes one block per host, e.g. github.com or k8s.io.
+	SubgroupHost
+	// SubgroupModule makes one block per module required in go.mod.
+	SubgroupModule
+)
+
+var subgroupModeWords = map[string]SubgroupMode{
+	"none":   SubgroupNone,
+	"host":   SubgroupHost,
+	"module": SubgroupModule,
+}
+
+func (m SubgroupMode) String() string {
+	for word, mode := range subgroupModeWords {
+		if mode == m {
+			return word
+		}
+	}
+	return "unknown"
+}
+
+// ParseSubgroupMode parses a thirdparty_subgroup value. An empty value is
+// SubgroupNone.
+func ParseSubgroupMode(s string) (SubgroupMode, error) {
+	if s == "" {
+		return SubgroupNone, nil
+	}
+	if mode, ok := subgroupModeWords[s]; ok {
+		return mode, nil
+	}
+	return 0, fmt.Errorf("invalid thirdparty subgroup: %s (want none, host or module)", s)
+}
@@ -6,8 +6,8 @@ _goreg_completions() {
     cur="${COMP_WORDS[COMP_CWORD]}"
     prev="${COMP_WORDS[COMP_CWORD-1]}"
 
-    opts="-h --help -v --version -w --write -l --local -o --order -n --organization -m --minimize-group -a --sort-include-alias -r --remove-import-comment"
-    subcommands="init"
+    opts="-h --help -v --version -w --write --check --verify --backup -l --local -o --order -n --organization -m --minimize-group -a --sort-include-alias -r --remove-import-comment --remove-redundant-alias --sort --thirdparty-subgroup --group-separator --single-import --emit-group-headers --alias-placement --gofmt --config --no-config --exclude --no-gitignore --include-generated --no-cache"
+    subcommands="init explain aliases config cache"
 
     # If we're at the first argument position, suggest subcommands and options
     if [[ ${COMP_CWORD} -eq 1 ]]; then
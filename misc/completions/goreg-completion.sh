@@ -8,7 +8,7 @@ if [ -n "$BASH_VERSION" ]; then
         cur="${COMP_WORDS[COMP_CWORD]}"
         prev="${COMP_WORDS[COMP_CWORD-1]}"
 
-        opts="-h --help -v --version -w --write -l --local -o --order -n --organization -m --minimize-group -a --sort-include-alias -r --remove-import-comment"
+        opts="-h --help -v --version -w --write --check --verify --backup -l --local -o --order -n --organization -m --minimize-group -a --sort-include-alias -r --remove-import-comment --remove-redundant-alias --sort --thirdparty-subgroup --group-separator --single-import --emit-group-headers --alias-placement --gofmt --config --no-config --exclude --no-gitignore --include-generated --no-cache"
 
         # Suggest options
         if [[ ${cur} == -* ]]; then
@@ -32,6 +32,9 @@ elif [ -n "$ZSH_VERSION" ]; then
             '--version[Show version]'
             '-w[Write formatted imports directly to the file]'
             '--write[Write formatted imports directly to the file]'
+            '--check[Report imports breaking the rules of goreg.toml]'
+            '--verify[Fail instead of writing if an import would be lost or altered]'
+            '--backup=-[Keep a copy of the original file when writing]::suffix:'
             '-l[Specify the local module path]:local module path:_files'
             '--local[Specify the local module path]:local module path:_files'
             '-o[Specify the order of import groups]:group order:(std thirdparty organization local)'
@@ -44,6 +47,20 @@ elif [ -n "$ZSH_VERSION" ]; then
             '--sort-include-alias[Sort imports with aliases within their respective groups]'
             '-r[Remove the comments in the import]'
             '--remove-import-comment[Remove the comments in the import]'
+            '--remove-redundant-alias[Drop aliases equal to the package name]'
+            '--sort[Sort imports within a group]:strategy:(lexical case-insensitive natural segment alias preserve)'
+            '--thirdparty-subgroup[Split third-party imports into blocks]:mode:(none host module)'
+            '--group-separator[Separate import groups]:separator:(blank none)'
+            '--single-import[Write a lone import]:mode:(preserve collapse expand)'
+            '--emit-group-headers[Write a header comment above each import group]'
+            '--alias-placement[Place blank, dot and aliased imports]:placement:'
+            '--gofmt[Format the whole file with gofmt before arranging imports]'
+            '--config[Use the specified goreg.toml]:config file:_files -g "*.toml"'
+            '--no-config[Do not use any goreg.toml]'
+            '--exclude[Skip files matching the pattern when walking a directory]:pattern:'
+            '--no-gitignore[Do not skip files ignored by .gitignore]'
+            '--include-generated[Also format generated files]'
+            '--no-cache[Do not use the cache of already formatted files]'
             ':Go file:_files -g "*.go"'
         )
         _arguments -s $arguments